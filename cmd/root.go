@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
 	"github.com/knipferrc/fm/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,7 +16,7 @@ import (
 )
 
 var rootCmd = &cobra.Command{
-	Use:     "fm",
+	Use:     "fm [path]",
 	Short:   "FM is a simple, configurable, and fun to use file manager",
 	Version: "0.15.3",
 	Args:    cobra.MaximumNArgs(1),
@@ -28,43 +31,140 @@ var rootCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		cfg, err := config.ParseConfig()
-		if err != nil {
-			log.Fatal(err)
+		var selectFile string
+		if len(args) == 1 {
+			startDir, selectFile, err = startPath(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
 		}
 
-		// If logging is enabled, logs will be output to debug.log.
+		// An invalid config is reported by the UI, which falls back to the default.
+		cfg, configErr := config.Load()
+
+		// If logging is enabled, logs will be output to fm.log in the config directory.
 		if cfg.Settings.EnableLogging {
-			f, err := tea.LogToFile("debug.log", "debug")
+			closeLog, err := openLog(cfg.Settings.LogLevel, cmd.Version)
 			if err != nil {
-				log.Fatal(err)
+				log.Printf("logging is disabled: %v", err)
+			} else {
+				defer closeLog()
 			}
+		}
 
-			defer func() {
-				if err = f.Close(); err != nil {
-					log.Fatal(err)
-				}
-			}()
+		if startDir == "" && cfg.Settings.RememberLastDir {
+			startDir = lastDir()
 		}
 
 		if startDir == "" {
-			startDir = cfg.Settings.StartDir
+			startDir, err = filesystem.ExpandPath(cfg.Settings.StartDir)
+			if err != nil {
+				log.Fatal(err)
+			}
 		}
 
-		m := tui.New(startDir, selectionPath)
+		m := tui.New(cfg, configErr, startDir, selectionPath, selectFile)
 		var opts []tea.ProgramOption
 
 		// Always append alt screen program option.
 		opts = append(opts, tea.WithAltScreen())
 
+		// Report clicks and the mouse wheel.
+		opts = append(opts, tea.WithMouseCellMotion())
+
 		// Initialize and start app.
 		p := tea.NewProgram(m, opts...)
 		if err := p.Start(); err != nil {
 			log.Fatal("Failed to start fm", err)
 		}
+
+		if cfg.Settings.RememberLastDir {
+			saveLastDir()
+		}
 	},
 }
 
+// openLog starts writing log lines of at least the given level to the log
+// file, returning a function which closes it.
+func openLog(levelName, version string) (func(), error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	level, levelErr := logger.ParseLevel(levelName)
+	if err := logger.Open(filepath.Join(dir, config.LogFileName), level); err != nil {
+		return nil, err
+	}
+
+	if levelErr != nil {
+		logger.Warn("using the info log level", "err", levelErr)
+	}
+
+	logger.Info("started fm", "version", version)
+
+	return func() {
+		if err := logger.Close(); err != nil {
+			log.Println(err)
+		}
+	}, nil
+}
+
+// startPath returns the directory to start in for a path given on the
+// command line, along with the name of the file to select if it is a file.
+func startPath(path string) (string, string, error) {
+	path, err := filesystem.ExpandPath(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	if info.IsDir() {
+		return path, "", nil
+	}
+
+	return filepath.Dir(path), filepath.Base(path), nil
+}
+
+// lastDir returns the directory the last session ended in, or an empty
+// string if it wasn't saved or no longer exists.
+func lastDir() string {
+	state, err := config.LoadState()
+	if err != nil {
+		logger.Error("loading the state failed", "err", err)
+		return ""
+	}
+
+	if state.LastDir == "" {
+		return ""
+	}
+
+	if err := filesystem.ValidateDirectory(state.LastDir); err != nil {
+		logger.Warn("not reopening the last directory", "dir", state.LastDir, "err", err)
+		return ""
+	}
+
+	return state.LastDir
+}
+
+// saveLastDir stores the directory the session ended in so the next one can start there.
+func saveLastDir() {
+	dir, err := os.Getwd()
+	if err != nil {
+		logger.Error("reading the current directory failed", "err", err)
+		return
+	}
+
+	if err := config.SaveState(config.State{LastDir: dir}); err != nil {
+		logger.Error("saving the state failed", "err", err)
+	}
+}
+
 // Execute runs the root command and starts the application.
 func Execute() {
 	rootCmd.AddCommand(updateCmd)
@@ -0,0 +1,114 @@
+// Package bookmarks implements storing frequently visited directories.
+package bookmarks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/knipferrc/fm/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the file bookmarks are stored in.
+const FileName = "bookmarks.yml"
+
+// Bookmark represents a bookmarked directory.
+type Bookmark struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+// filePath returns the path of the bookmarks file.
+func filePath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, FileName), nil
+}
+
+// save writes the bookmarks to the bookmarks file.
+func save(bookmarks []Bookmark) error {
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(bookmarks)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// List returns all bookmarks.
+func List() ([]Bookmark, error) {
+	path, err := filePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []Bookmark
+	if err := yaml.Unmarshal(data, &bookmarks); err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+// Add bookmarks a directory, replacing any existing bookmark for the same path.
+// The base name of the directory is used if name is empty.
+func Add(name, path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = filepath.Base(absPath)
+	}
+
+	bookmarks, err := List()
+	if err != nil {
+		return err
+	}
+
+	for i, bookmark := range bookmarks {
+		if bookmark.Path == absPath {
+			bookmarks[i].Name = name
+			return save(bookmarks)
+		}
+	}
+
+	return save(append(bookmarks, Bookmark{Name: name, Path: absPath}))
+}
+
+// Remove removes the bookmark for the given path.
+func Remove(path string) error {
+	bookmarks, err := List()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]Bookmark, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if bookmark.Path != path {
+			remaining = append(remaining, bookmark)
+		}
+	}
+
+	return save(remaining)
+}
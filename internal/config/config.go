@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
@@ -16,6 +15,9 @@ const AppDir = "fm"
 // ConfigFileName is the name of the config file that gets created.
 const ConfigFileName = "config.yml"
 
+// LogFileName is the name of the file logs are written to when logging is enabled.
+const LogFileName = "fm.log"
+
 // SyntaxThemeConfig represents light and dark syntax themes.
 type SyntaxThemeConfig struct {
 	Light string `yaml:"light"`
@@ -24,11 +26,40 @@ type SyntaxThemeConfig struct {
 
 // SettingsConfig struct represents the config for the settings.
 type SettingsConfig struct {
-	StartDir       string `yaml:"start_dir"`
-	ShowIcons      bool   `yaml:"show_icons"`
-	EnableLogging  bool   `yaml:"enable_logging"`
-	PrettyMarkdown bool   `yaml:"pretty_markdown"`
-	Borderless     bool   `yaml:"borderless"`
+	StartDir           string `yaml:"start_dir"`
+	ShowIcons          bool   `yaml:"show_icons"`
+	IconSet            string `yaml:"icon_set"`
+	EnableLogging      bool   `yaml:"enable_logging"`
+	LogLevel           string `yaml:"log_level"`
+	PrettyMarkdown     bool   `yaml:"pretty_markdown"`
+	Borderless         bool   `yaml:"borderless"`
+	SyntaxHighlighting bool   `yaml:"syntax_highlighting"`
+	UseTrash           bool   `yaml:"use_trash"`
+	ConfirmDelete      bool   `yaml:"confirm_delete"`
+	StickySelection    bool   `yaml:"sticky_selection"`
+	QuitOnLastTabClose bool   `yaml:"quit_on_last_tab_close"`
+	RememberLastDir    bool   `yaml:"remember_last_dir"`
+	WatchDirectory     bool   `yaml:"watch_directory"`
+	ShowGitStatus      bool   `yaml:"show_git_status"`
+	ShowImageMetadata  bool   `yaml:"show_image_metadata"`
+	ShowDiskSpace      bool   `yaml:"show_disk_space"`
+	AutoPreview        bool   `yaml:"auto_preview"`
+	CaseSensitiveSort  bool   `yaml:"case_sensitive_sort"`
+	AutoPreviewDelay   int    `yaml:"auto_preview_delay"`
+	FinderShowHidden   bool   `yaml:"finder_show_hidden"`
+	FinderGitignore    bool   `yaml:"finder_respect_gitignore"`
+	ExactSizes         bool   `yaml:"exact_sizes"`
+	WrapPreview        bool   `yaml:"wrap_preview"`
+	PreviewLineNumbers bool   `yaml:"preview_line_numbers"`
+	MaxPreviewBytes    int64  `yaml:"max_preview_bytes"`
+	MaxImagePixels     int64  `yaml:"max_image_pixels"`
+	RenderSVG          bool   `yaml:"render_svg"`
+	Editor             string `yaml:"editor"`
+	Shell              string `yaml:"shell"`
+	Layout             string `yaml:"layout"`
+	SplitRatio         int    `yaml:"split_ratio"`
+	ScrollOff          int    `yaml:"scroll_off"`
+	StatusSegment      string `yaml:"status_segment"`
 }
 
 // ThemeConfig represents the config for themes.
@@ -39,8 +70,10 @@ type ThemeConfig struct {
 
 // Config represents the main config for the application.
 type Config struct {
-	Settings SettingsConfig `yaml:"settings"`
-	Theme    ThemeConfig    `yaml:"theme"`
+	Settings    SettingsConfig      `yaml:"settings"`
+	Theme       ThemeConfig         `yaml:"theme"`
+	Keybindings map[string][]string `yaml:"keybindings,omitempty"`
+	Previewers  map[string]string   `yaml:"previewers,omitempty"`
 }
 
 // configError represents an error that occurred while parsing the config file.
@@ -57,11 +90,40 @@ type ConfigParser struct{}
 func (parser ConfigParser) getDefaultConfig() Config {
 	return Config{
 		Settings: SettingsConfig{
-			StartDir:       ".",
-			ShowIcons:      true,
-			EnableLogging:  false,
-			PrettyMarkdown: true,
-			Borderless:     false,
+			StartDir:           ".",
+			ShowIcons:          true,
+			IconSet:            "auto",
+			EnableLogging:      false,
+			LogLevel:           "info",
+			PrettyMarkdown:     true,
+			Borderless:         false,
+			SyntaxHighlighting: true,
+			UseTrash:           false,
+			ConfirmDelete:      true,
+			StickySelection:    false,
+			QuitOnLastTabClose: false,
+			RememberLastDir:    false,
+			WatchDirectory:     false,
+			ShowGitStatus:      false,
+			ShowImageMetadata:  false,
+			ShowDiskSpace:      false,
+			AutoPreview:        false,
+			AutoPreviewDelay:   150,
+			CaseSensitiveSort:  false,
+			FinderShowHidden:   false,
+			FinderGitignore:    true,
+			ExactSizes:         false,
+			WrapPreview:        true,
+			PreviewLineNumbers: false,
+			MaxPreviewBytes:    1024 * 1024,
+			MaxImagePixels:     50 * 1000 * 1000,
+			RenderSVG:          false,
+			Editor:             "",
+			Shell:              "",
+			Layout:             "horizontal",
+			SplitRatio:         50,
+			ScrollOff:          0,
+			StatusSegment:      "",
 		},
 		Theme: ThemeConfig{
 			AppTheme: "default",
@@ -91,7 +153,7 @@ Example of a config.yml file:
 For more info, go to https://github.com/knipferrc/fm
 press q to exit.
 Original error: %v`,
-		path.Join(e.configDir, AppDir, ConfigFileName),
+		filepath.Join(e.configDir, AppDir, ConfigFileName),
 		e.parser.getDefaultConfigYamlContents(),
 		e.err,
 	)
@@ -123,16 +185,47 @@ func (parser ConfigParser) createConfigFileIfMissing(configFilePath string) erro
 	return nil
 }
 
+// getConfigHome returns the base directory for user specific config files.
+// XDG_CONFIG_HOME is only used if it is an absolute path, as the XDG base
+// directory spec requires, otherwise the default of the platform is used.
+func getConfigHome() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return configHome, nil
+	}
+
+	return os.UserConfigDir()
+}
+
+// Dir returns the directory fm stores its files in, creating it if it doesn't exist.
+func Dir() (string, error) {
+	configHome, err := getConfigHome()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configHome, AppDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// FilePath returns the path of the config file.
+func FilePath() (string, error) {
+	configHome, err := getConfigHome()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configHome, AppDir, ConfigFileName), nil
+}
+
 // getConfigFileOrCreateIfMissing returns the config file path or creates the config file if it doesn't exist.
 func (parser ConfigParser) getConfigFileOrCreateIfMissing() (*string, error) {
-	var err error
-	configDir := os.Getenv("XDG_CONFIG_HOME")
-
-	if configDir == "" {
-		configDir, err = os.UserConfigDir()
-		if err != nil {
-			return nil, configError{parser: parser, configDir: configDir, err: err}
-		}
+	configDir, err := getConfigHome()
+	if err != nil {
+		return nil, configError{parser: parser, configDir: configDir, err: err}
 	}
 
 	prsConfigDir := filepath.Join(configDir, AppDir)
@@ -160,6 +253,11 @@ func (e parsingError) Error() string {
 	return fmt.Sprintf("failed parsing config.yml: %v", e.err)
 }
 
+// Unwrap returns the error which caused the parsing to fail.
+func (e parsingError) Unwrap() error {
+	return e.err
+}
+
 // readConfigFile reads the config file and returns the config.
 func (parser ConfigParser) readConfigFile(path string) (Config, error) {
 	config := parser.getDefaultConfig()
@@ -196,3 +294,20 @@ func ParseConfig() (Config, error) {
 
 	return config, nil
 }
+
+// Load parses the config file like ParseConfig, but falls back to the
+// default config if the config directory can't be created or the config
+// file can't be read or parsed, returning the reason alongside it.
+func Load() (Config, error) {
+	config, err := ParseConfig()
+	if err == nil {
+		return config, nil
+	}
+
+	var cfgErr configError
+	if errors.As(err, &cfgErr) {
+		err = cfgErr.err
+	}
+
+	return initParser().getDefaultConfig(), err
+}
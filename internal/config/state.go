@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StateFileName is the name of the file the state of the last session is stored in.
+const StateFileName = "state.yml"
+
+// State represents the state fm keeps between sessions.
+type State struct {
+	LastDir string `yaml:"last_dir"`
+}
+
+// stateFilePath returns the path of the state file.
+func stateFilePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, StateFileName), nil
+}
+
+// LoadState returns the state of the last session, which is empty if none was saved.
+func LoadState() (State, error) {
+	var state State
+
+	path, err := stateFilePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return state, err
+	}
+
+	err = yaml.Unmarshal(data, &state)
+	return state, err
+}
+
+// SaveState writes the state of the current session to the state file.
+func SaveState(state State) error {
+	path, err := stateFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
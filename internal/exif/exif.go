@@ -0,0 +1,324 @@
+// Package exif reads the camera, the date taken and the GPS position from
+// the EXIF metadata of JPEG and PNG images.
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxExifSize is the largest size of the EXIF data read from an image, the
+// most a JPEG APP1 segment can hold.
+const maxExifSize = 64 * 1024
+
+const (
+	tagMake             = 0x010f
+	tagModel            = 0x0110
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagGPSLatitudeRef   = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLongitudeRef  = 0x0003
+	tagGPSLongitude     = 0x0004
+
+	typeASCII    = 2
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+)
+
+// ErrNoMetadata is returned when an image has no EXIF metadata.
+var ErrNoMetadata = errors.New("no exif metadata")
+
+// errInvalid is returned when the EXIF metadata of an image is malformed.
+var errInvalid = errors.New("invalid exif metadata")
+
+// pngSignature is the signature every PNG file starts with.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// typeSizes maps the supported value types to their size in bytes.
+var typeSizes = map[uint16]uint64{
+	1:  1, // BYTE
+	2:  1, // ASCII
+	3:  2, // SHORT
+	4:  4, // LONG
+	5:  8, // RATIONAL
+	7:  1, // UNDEFINED
+	9:  4, // SLONG
+	10: 8, // SRATIONAL
+}
+
+// Metadata represents the EXIF fields of an image. Fields missing from the
+// image are left empty.
+type Metadata struct {
+	Make      string
+	Model     string
+	DateTaken string
+	Latitude  float64
+	Longitude float64
+	HasGPS    bool
+}
+
+// entry represents a single field of an image file directory.
+type entry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// tiff represents the TIFF structure EXIF metadata is stored in.
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// Read returns the EXIF metadata of a JPEG or PNG image, returning
+// ErrNoMetadata if it has none.
+func Read(path string) (Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	head, err := r.Peek(len(pngSignature))
+	if err != nil {
+		return Metadata{}, ErrNoMetadata
+	}
+
+	var data []byte
+	switch {
+	case bytes.Equal(head, pngSignature):
+		data, err = pngExif(r)
+	case head[0] == 0xff && head[1] == 0xd8:
+		data, err = jpegExif(r)
+	default:
+		return Metadata{}, ErrNoMetadata
+	}
+
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	return parse(data)
+}
+
+// jpegExif returns the TIFF data of the APP1 segment of a JPEG image,
+// stopping at the start of the image data.
+func jpegExif(r io.Reader) ([]byte, error) {
+	var marker [2]byte
+	if _, err := io.ReadFull(r, marker[:]); err != nil {
+		return nil, ErrNoMetadata
+	}
+
+	for {
+		if _, err := io.ReadFull(r, marker[:]); err != nil || marker[0] != 0xff {
+			return nil, ErrNoMetadata
+		}
+
+		// Start of scan and end of image, no metadata follows.
+		if marker[1] == 0xda || marker[1] == 0xd9 {
+			return nil, ErrNoMetadata
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil || length < 2 {
+			return nil, ErrNoMetadata
+		}
+
+		segment := make([]byte, length-2)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, ErrNoMetadata
+		}
+
+		if marker[1] == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// pngExif returns the TIFF data of the eXIf chunk of a PNG image, stopping
+// at the start of the image data.
+func pngExif(r io.Reader) ([]byte, error) {
+	if _, err := io.CopyN(io.Discard, r, int64(len(pngSignature))); err != nil {
+		return nil, ErrNoMetadata
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, ErrNoMetadata
+		}
+
+		length := binary.BigEndian.Uint32(header[:4])
+		switch string(header[4:]) {
+		case "eXIf":
+			if length > maxExifSize {
+				return nil, errInvalid
+			}
+
+			data := make([]byte, length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, ErrNoMetadata
+			}
+
+			return data, nil
+		case "IDAT", "IEND":
+			return nil, ErrNoMetadata
+		}
+
+		// Skip the chunk and its CRC.
+		if _, err := io.CopyN(io.Discard, r, int64(length)+4); err != nil {
+			return nil, ErrNoMetadata
+		}
+	}
+}
+
+// parse reads the metadata from the TIFF data of an image.
+func parse(data []byte) (Metadata, error) {
+	if len(data) < 8 {
+		return Metadata{}, errInvalid
+	}
+
+	t := tiff{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return Metadata{}, errInvalid
+	}
+
+	ifd0, err := t.ifd(t.order.Uint32(data[4:]))
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	meta := Metadata{
+		Make:      t.str(ifd0[tagMake]),
+		Model:     t.str(ifd0[tagModel]),
+		DateTaken: t.str(ifd0[tagDateTime]),
+	}
+
+	if offset, ok := t.long(ifd0[tagExifIFD]); ok {
+		if exifIFD, err := t.ifd(offset); err == nil {
+			if date := t.str(exifIFD[tagDateTimeOriginal]); date != "" {
+				meta.DateTaken = date
+			}
+		}
+	}
+
+	if offset, ok := t.long(ifd0[tagGPSIFD]); ok {
+		if gpsIFD, err := t.ifd(offset); err == nil {
+			lat, latOK := t.coordinate(gpsIFD[tagGPSLatitude], t.str(gpsIFD[tagGPSLatitudeRef]), "S")
+			long, longOK := t.coordinate(gpsIFD[tagGPSLongitude], t.str(gpsIFD[tagGPSLongitudeRef]), "W")
+			if latOK && longOK {
+				meta.Latitude, meta.Longitude, meta.HasGPS = lat, long, true
+			}
+		}
+	}
+
+	return meta, nil
+}
+
+// ifd returns the entries of the image file directory at the given offset,
+// skipping entries of unsupported types or pointing outside of the data.
+func (t tiff) ifd(offset uint32) (map[uint16]entry, error) {
+	start := uint64(offset)
+	if start+2 > uint64(len(t.data)) {
+		return nil, errInvalid
+	}
+
+	count := uint64(t.order.Uint16(t.data[start:]))
+	entries := make(map[uint16]entry, count)
+
+	for i := uint64(0); i < count; i++ {
+		pos := start + 2 + i*12
+		if pos+12 > uint64(len(t.data)) {
+			return nil, errInvalid
+		}
+
+		raw := t.data[pos : pos+12]
+		typ := t.order.Uint16(raw[2:])
+		n := t.order.Uint32(raw[4:])
+
+		size, ok := typeSizes[typ]
+		if !ok {
+			continue
+		}
+
+		length := size * uint64(n)
+		value := raw[8:12]
+		if length > 4 {
+			valueOffset := uint64(t.order.Uint32(raw[8:]))
+			if valueOffset+length > uint64(len(t.data)) {
+				continue
+			}
+
+			value = t.data[valueOffset : valueOffset+length]
+		} else {
+			value = value[:length]
+		}
+
+		entries[t.order.Uint16(raw)] = entry{typ: typ, count: n, value: value}
+	}
+
+	return entries, nil
+}
+
+// str returns the value of an ASCII entry without its trailing NUL and spaces.
+func (t tiff) str(e entry) string {
+	if e.typ != typeASCII {
+		return ""
+	}
+
+	return strings.TrimRight(string(e.value), "\x00 ")
+}
+
+// long returns the value of a SHORT or LONG entry.
+func (t tiff) long(e entry) (uint32, bool) {
+	switch {
+	case e.typ == typeLong && len(e.value) >= 4:
+		return t.order.Uint32(e.value), true
+	case e.typ == typeShort && len(e.value) >= 2:
+		return uint32(t.order.Uint16(e.value)), true
+	default:
+		return 0, false
+	}
+}
+
+// coordinate returns the decimal degrees of a GPS coordinate stored as
+// degrees, minutes and seconds, negated if its reference is negativeRef.
+func (t tiff) coordinate(e entry, ref, negativeRef string) (float64, bool) {
+	if e.typ != typeRational || e.count != 3 {
+		return 0, false
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		numerator := t.order.Uint32(e.value[i*8:])
+		denominator := t.order.Uint32(e.value[i*8+4:])
+		if denominator == 0 {
+			return 0, false
+		}
+
+		parts[i] = float64(numerator) / float64(denominator)
+	}
+
+	degrees := parts[0] + parts[1]/60 + parts[2]/3600
+	if ref == negativeRef {
+		degrees = -degrees
+	}
+
+	return degrees, true
+}
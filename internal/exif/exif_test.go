@@ -0,0 +1,330 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testEntry represents a field of an image file directory built by tiffData.
+// If ifd is set, the value is the offset of the directory with that index.
+type testEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+	ifd   int
+}
+
+// appendUint16 appends v to b in the given byte order.
+func appendUint16(order binary.ByteOrder, b []byte, v uint16) []byte {
+	var buf [2]byte
+	order.PutUint16(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+// appendUint32 appends v to b in the given byte order.
+func appendUint32(order binary.ByteOrder, b []byte, v uint32) []byte {
+	var buf [4]byte
+	order.PutUint32(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+// tiffData returns TIFF data holding the given image file directories, each
+// followed by the values which don't fit into its entries.
+func tiffData(order binary.ByteOrder, ifds ...[]testEntry) []byte {
+	offsets := make([]uint32, len(ifds))
+	offset := uint32(8)
+	for i, ifd := range ifds {
+		offsets[i] = offset
+		offset += 2 + 12*uint32(len(ifd)) + 4
+
+		for _, e := range ifd {
+			if len(e.value) > 4 {
+				offset += uint32(len(e.value))
+			}
+		}
+	}
+
+	data := []byte("II")
+	if order == binary.BigEndian {
+		data = []byte("MM")
+	}
+
+	data = appendUint16(order, data, 42)
+	data = appendUint32(order, data, offsets[0])
+
+	for i, ifd := range ifds {
+		valueOffset := offsets[i] + 2 + 12*uint32(len(ifd)) + 4
+
+		var values []byte
+		data = appendUint16(order, data, uint16(len(ifd)))
+		for _, e := range ifd {
+			data = appendUint16(order, data, e.tag)
+			data = appendUint16(order, data, e.typ)
+			data = appendUint32(order, data, e.count)
+
+			switch {
+			case e.ifd > 0:
+				data = appendUint32(order, data, offsets[e.ifd])
+			case len(e.value) > 4:
+				data = appendUint32(order, data, valueOffset+uint32(len(values)))
+				values = append(values, e.value...)
+			default:
+				var inline [4]byte
+				copy(inline[:], e.value)
+				data = append(data, inline[:]...)
+			}
+		}
+
+		data = appendUint32(order, data, 0)
+		data = append(data, values...)
+	}
+
+	return data
+}
+
+// ascii returns an ASCII entry holding s.
+func ascii(tag uint16, s string) testEntry {
+	return testEntry{tag: tag, typ: typeASCII, count: uint32(len(s) + 1), value: append([]byte(s), 0)}
+}
+
+// rationals returns a RATIONAL entry holding the given numerators and
+// denominators.
+func rationals(order binary.ByteOrder, tag uint16, parts ...uint32) testEntry {
+	var value []byte
+	for _, part := range parts {
+		value = appendUint32(order, value, part)
+	}
+
+	return testEntry{tag: tag, typ: typeRational, count: uint32(len(parts) / 2), value: value}
+}
+
+// gpsIFD returns the GPS directory of a position 10°30' north, 20°15' west.
+func gpsIFD(order binary.ByteOrder, latitudeDenominator uint32) []testEntry {
+	return []testEntry{
+		ascii(tagGPSLatitudeRef, "N"),
+		rationals(order, tagGPSLatitude, 10, 1, 30, latitudeDenominator, 0, 1),
+		ascii(tagGPSLongitudeRef, "W"),
+		rationals(order, tagGPSLongitude, 20, 1, 15, 1, 0, 1),
+	}
+}
+
+func TestParse(t *testing.T) {
+	le, be := binary.LittleEndian, binary.BigEndian
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Metadata
+		wantErr error
+	}{
+		{
+			name: "little endian",
+			data: tiffData(le, []testEntry{ascii(tagMake, "Canon"), ascii(tagModel, "EOS 5D"), ascii(tagDateTime, "2020:01:02 03:04:05")}),
+			want: Metadata{Make: "Canon", Model: "EOS 5D", DateTaken: "2020:01:02 03:04:05"},
+		},
+		{
+			name: "big endian",
+			data: tiffData(be, []testEntry{ascii(tagMake, "Nikon Corp"), ascii(tagModel, "D1")}),
+			want: Metadata{Make: "Nikon Corp", Model: "D1"},
+		},
+		{
+			name: "date taken from the exif directory",
+			data: tiffData(le,
+				[]testEntry{ascii(tagDateTime, "2020:01:02 03:04:05"), {tag: tagExifIFD, typ: typeLong, count: 1, ifd: 1}},
+				[]testEntry{ascii(tagDateTimeOriginal, "2019:12:31 23:59:59")},
+			),
+			want: Metadata{DateTaken: "2019:12:31 23:59:59"},
+		},
+		{
+			name: "gps position",
+			data: tiffData(be, []testEntry{{tag: tagGPSIFD, typ: typeLong, count: 1, ifd: 1}}, gpsIFD(be, 1)),
+			want: Metadata{Latitude: 10.5, Longitude: -20.25, HasGPS: true},
+		},
+		{
+			name: "gps position with a zero denominator",
+			data: tiffData(le, []testEntry{{tag: tagGPSIFD, typ: typeLong, count: 1, ifd: 1}}, gpsIFD(le, 0)),
+			want: Metadata{},
+		},
+		{
+			name: "exif directory outside of the data",
+			data: tiffData(le, []testEntry{ascii(tagMake, "Canon"), {tag: tagExifIFD, typ: typeLong, count: 1, value: appendUint32(le, nil, 1<<20)}}),
+			want: Metadata{Make: "Canon"},
+		},
+		{
+			name: "value outside of the data",
+			data: tiffData(le, []testEntry{{tag: tagMake, typ: typeASCII, count: 1 << 30, value: appendUint32(le, nil, 8)}, ascii(tagModel, "X")}),
+			want: Metadata{Model: "X"},
+		},
+		{
+			name: "unsupported type",
+			data: tiffData(le, []testEntry{{tag: tagMake, typ: 99, count: 1}, ascii(tagModel, "X")}),
+			want: Metadata{Model: "X"},
+		},
+		{
+			name: "rational with the wrong count",
+			data: tiffData(le,
+				[]testEntry{{tag: tagGPSIFD, typ: typeLong, count: 1, ifd: 1}},
+				[]testEntry{ascii(tagGPSLatitudeRef, "N"), rationals(le, tagGPSLatitude, 10, 1), ascii(tagGPSLongitudeRef, "E"), rationals(le, tagGPSLongitude, 20, 1)},
+			),
+			want: Metadata{},
+		},
+		{
+			name:    "truncated header",
+			data:    []byte("II*\x00"),
+			wantErr: errInvalid,
+		},
+		{
+			name:    "unknown byte order",
+			data:    []byte("XX*\x00\x08\x00\x00\x00"),
+			wantErr: errInvalid,
+		},
+		{
+			name:    "first directory outside of the data",
+			data:    []byte("II*\x00\xff\x00\x00\x00"),
+			wantErr: errInvalid,
+		},
+		{
+			name:    "truncated directory",
+			data:    tiffData(le, []testEntry{ascii(tagMake, "Canon"), ascii(tagModel, "EOS 5D")})[:20],
+			wantErr: errInvalid,
+		},
+		{
+			name:    "directory count beyond the data",
+			data:    []byte("II*\x00\x08\x00\x00\x00\xff\xff"),
+			wantErr: errInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parse failed with %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// pngChunk returns a PNG chunk with the given type and data and a zero CRC.
+func pngChunk(typ string, data []byte) []byte {
+	chunk := appendUint32(binary.BigEndian, nil, uint32(len(data)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, data...)
+
+	return append(chunk, 0, 0, 0, 0)
+}
+
+// jpegSegment returns a JPEG segment with the given marker and data.
+func jpegSegment(marker byte, data []byte) []byte {
+	segment := []byte{0xff, marker}
+	segment = appendUint16(binary.BigEndian, segment, uint16(len(data)+2))
+
+	return append(segment, data...)
+}
+
+// concat returns the parts joined together.
+func concat(parts ...[]byte) []byte {
+	var data []byte
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+
+	return data
+}
+
+func TestRead(t *testing.T) {
+	tiff := tiffData(binary.LittleEndian, []testEntry{ascii(tagMake, "Canon")})
+	exifSegment := append([]byte("Exif\x00\x00"), tiff...)
+	soi := []byte{0xff, 0xd8}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Metadata
+		wantErr error
+	}{
+		{
+			name: "jpeg",
+			data: concat(soi, jpegSegment(0xe0, []byte("JFIF\x00")), jpegSegment(0xe1, exifSegment), jpegSegment(0xda, nil)),
+			want: Metadata{Make: "Canon"},
+		},
+		{
+			name:    "jpeg without exif",
+			data:    concat(soi, jpegSegment(0xe0, []byte("JFIF\x00")), jpegSegment(0xda, nil)),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "jpeg with a truncated segment",
+			data:    concat(soi, jpegSegment(0xe1, exifSegment))[:20],
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "jpeg with a segment length below its own size",
+			data:    concat(soi, []byte{0xff, 0xe1, 0x00, 0x01}),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "jpeg with a malformed exif segment",
+			data:    concat(soi, jpegSegment(0xe1, []byte("Exif\x00\x00II*\x00")), jpegSegment(0xda, nil)),
+			wantErr: errInvalid,
+		},
+		{
+			name: "png",
+			data: concat(pngSignature, pngChunk("IHDR", make([]byte, 13)), pngChunk("eXIf", tiff), pngChunk("IDAT", nil)),
+			want: Metadata{Make: "Canon"},
+		},
+		{
+			name:    "png with exif after the image data",
+			data:    concat(pngSignature, pngChunk("IDAT", nil), pngChunk("eXIf", tiff)),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "png with a truncated exif chunk",
+			data:    concat(pngSignature, pngChunk("eXIf", tiff))[:30],
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "png with an oversized exif chunk",
+			data:    concat(pngSignature, []byte{0xff, 0xff, 0xff, 0xff}, []byte("eXIf")),
+			wantErr: errInvalid,
+		},
+		{
+			name:    "not an image",
+			data:    []byte("plain text, not an image"),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "empty",
+			data:    nil,
+			wantErr: ErrNoMetadata,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "image")
+			if err := os.WriteFile(path, tt.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Read(path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read failed with %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Read = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/knipferrc/fm/internal/sortorder"
+	"github.com/knipferrc/fm/internal/strfmt"
+)
+
+// maxArchiveEntryBytes is the most bytes read from a file within an archive,
+// as the whole file is held in memory.
+const maxArchiveEntryBytes = 16 * 1024 * 1024
+
+// archiveEntry represents a single file within an archive.
+type archiveEntry struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// ArchiveItem represents a file or directory directly inside a directory of
+// an archive. Path is slash separated and relative to the archive root.
+type ArchiveItem struct {
+	Name  string
+	Path  string
+	IsDir bool
+	Size  int64
+}
+
+// IsArchive returns true if the file name has an extension of a supported archive.
+func IsArchive(name string) bool {
+	return isZip(name) || isTar(name)
+}
+
+// isZip returns true if the file name is that of a zip archive.
+func isZip(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".zip")
+}
+
+// isTar returns true if the file name is that of a tar archive, compressed or not.
+func isTar(name string) bool {
+	lower := strings.ToLower(name)
+
+	return strings.HasSuffix(lower, ".tar") || isGzippedTar(lower)
+}
+
+// isGzippedTar returns true if the file name is that of a gzip compressed tar archive.
+func isGzippedTar(name string) bool {
+	lower := strings.ToLower(name)
+
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// readZipEntries reads the entries of a zip archive from its central directory.
+func readZipEntries(path string) ([]archiveEntry, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	entries := make([]archiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		entries = append(entries, archiveEntry{
+			name:    file.Name,
+			size:    int64(file.UncompressedSize64),
+			mode:    file.Mode(),
+			modTime: file.Modified,
+		})
+	}
+
+	return entries, nil
+}
+
+// readTarEntries reads the headers of a tar archive without extracting any files.
+func readTarEntries(path string) ([]archiveEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if isGzippedTar(path) {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		r = gzipReader
+	}
+
+	var entries []archiveEntry
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, archiveEntry{
+			name:    header.Name,
+			size:    header.Size,
+			mode:    header.FileInfo().Mode(),
+			modTime: header.ModTime,
+		})
+	}
+}
+
+// readArchiveEntries reads the entries of a zip or tar archive, which
+// includes Office documents.
+func readArchiveEntries(path string) ([]archiveEntry, error) {
+	switch {
+	case isZip(path) || IsOfficeDocument(path):
+		return readZipEntries(path)
+	case isTar(path):
+		return readTarEntries(path)
+	}
+
+	return nil, fmt.Errorf("%s is not a supported archive", path)
+}
+
+// ListArchive returns a formatted listing of the entries of a zip or tar
+// archive, with sizes in the given format.
+func ListArchive(path string, sizeFormat strfmt.SizeFormat) (string, error) {
+	entries, err := readArchiveEntries(path)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		sb        strings.Builder
+		totalSize int64
+	)
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		totalSize += entry.size
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			entry.mode,
+			strfmt.FormatSize(entry.size, sizeFormat),
+			entry.modTime.Format("2006-01-02 15:04"),
+			entry.name,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(&sb, "\n%d entries, %s uncompressed", len(entries), strfmt.FormatSize(totalSize, sizeFormat))
+
+	return sb.String(), nil
+}
+
+// cleanArchiveName returns the name of an archive entry without a leading
+// "./" or slashes, and whether it names a directory.
+func cleanArchiveName(entry archiveEntry) (string, bool) {
+	name := strings.TrimPrefix(entry.name, "./")
+	isDir := entry.mode.IsDir() || strings.HasSuffix(name, "/")
+
+	return strings.Trim(name, "/"), isDir
+}
+
+// ListArchiveDir returns the files and directories directly inside dir, a
+// slash separated path within the archive which is empty for its root.
+// Directories which are only implied by the paths of files are listed too.
+// Directories are listed first, each group sorted by name in natural order,
+// ignoring case unless caseSensitive is set.
+func ListArchiveDir(path, dir string, caseSensitive bool) ([]ArchiveItem, error) {
+	entries, err := readArchiveEntries(path)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	var items []ArchiveItem
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name, isDir := cleanArchiveName(entry)
+		if name == "" || !strings.HasPrefix(name, prefix) || name == dir {
+			continue
+		}
+
+		rest := strings.TrimPrefix(name, prefix)
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			rest, isDir = rest[:i], true
+		}
+
+		if seen[rest] {
+			continue
+		}
+
+		seen[rest] = true
+
+		item := ArchiveItem{Name: rest, Path: prefix + rest, IsDir: isDir}
+		if !isDir {
+			item.Size = entry.size
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].IsDir != items[j].IsDir {
+			return items[i].IsDir
+		}
+
+		return sortorder.Less(items[i].Name, items[j].Name, caseSensitive)
+	})
+
+	return items, nil
+}
+
+// readLimitedFrom reads up to limit bytes from r, reporting whether there
+// was more to read.
+func readLimitedFrom(r io.Reader, limit int64) ([]byte, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(data)) <= limit {
+		return data, false, nil
+	}
+
+	return trimCutRune(data[:limit]), true, nil
+}
+
+// ReadArchiveFile reads up to limit bytes of the file at name, a slash
+// separated path within the archive, without extracting it to disk. A limit
+// of zero or less, or above maxArchiveEntryBytes, reads maxArchiveEntryBytes.
+func ReadArchiveFile(path, name string, limit int64) ([]byte, bool, error) {
+	if limit <= 0 || limit > maxArchiveEntryBytes {
+		limit = maxArchiveEntryBytes
+	}
+
+	switch {
+	case isZip(path):
+		return readZipFile(path, name, limit)
+	case isTar(path):
+		return readTarFile(path, name, limit)
+	}
+
+	return nil, false, fmt.Errorf("%s is not a supported archive", path)
+}
+
+// readZipFile reads up to limit bytes of a file within a zip archive.
+func readZipFile(path, name string, limit int64) ([]byte, bool, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		fileName, isDir := cleanArchiveName(archiveEntry{name: file.Name, mode: file.Mode()})
+		if isDir || fileName != name {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, false, err
+		}
+		defer rc.Close()
+
+		return readLimitedFrom(rc, limit)
+	}
+
+	return nil, false, fmt.Errorf("%s is not in %s", name, filepath.Base(path))
+}
+
+// readTarFile reads up to limit bytes of a file within a tar archive.
+func readTarFile(path, name string, limit int64) ([]byte, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if isGzippedTar(path) {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, false, err
+		}
+		defer gzipReader.Close()
+
+		r = gzipReader
+	}
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, false, fmt.Errorf("%s is not in %s", name, filepath.Base(path))
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		entry := archiveEntry{name: header.Name, mode: header.FileInfo().Mode()}
+		if fileName, isDir := cleanArchiveName(entry); !isDir && fileName == name {
+			return readLimitedFrom(tarReader, limit)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"io"
+	"net/http"
+	"os"
+)
+
+// sniffLength is the number of bytes read from the start of a file to
+// determine if it is binary.
+const sniffLength = 8192
+
+// ReadPrefix returns up to n bytes from the start of a file.
+func ReadPrefix(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, n)
+	read, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	return buf[:read], nil
+}
+
+// readHead returns up to sniffLength bytes from the start of a file.
+func readHead(path string) ([]byte, error) {
+	return ReadPrefix(path, sniffLength)
+}
+
+// IsBinary returns true if the start of a file is neither UTF-16 with a
+// byte order mark, nor UTF-8 or Latin-1 text without NUL bytes.
+func IsBinary(path string) bool {
+	head, err := readHead(path)
+	if err != nil {
+		return false
+	}
+
+	_, ok := detectEncoding(head, len(head) == sniffLength)
+
+	return !ok
+}
+
+// ContentType returns the MIME type of a file based on its first bytes.
+func ContentType(path string) (string, error) {
+	head, err := readHead(path)
+	if err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(head), nil
+}
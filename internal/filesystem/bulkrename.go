@@ -0,0 +1,140 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RenamePair represents an item to rename and its new path.
+type RenamePair struct {
+	Src string
+	Dst string
+}
+
+// ApplyRenamePattern returns the new path of each item for a pattern in
+// which {n} is replaced by a counter starting at 1, zero padded to the width
+// of the last number, {name} by the name of the item without its extension
+// and {ext} by its extension including the dot. Items keep their directory.
+func ApplyRenamePattern(pattern string, paths []string) ([]RenamePair, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("a pattern is required")
+	}
+
+	if strings.ContainsRune(pattern, filepath.Separator) || strings.ContainsRune(pattern, '/') {
+		return nil, fmt.Errorf("%q must not contain a path separator", pattern)
+	}
+
+	width := len(strconv.Itoa(len(paths)))
+	pairs := make([]RenamePair, 0, len(paths))
+
+	for i, path := range paths {
+		name := filepath.Base(path)
+		ext := filepath.Ext(name)
+		if ext == name {
+			ext = ""
+		}
+
+		newName := strings.NewReplacer(
+			"{n}", fmt.Sprintf("%0*d", width, i+1),
+			"{name}", strings.TrimSuffix(name, ext),
+			"{ext}", ext,
+		).Replace(pattern)
+
+		if newName == "." || newName == ".." {
+			return nil, fmt.Errorf("%s can't be renamed to %q", name, newName)
+		}
+
+		pairs = append(pairs, RenamePair{Src: path, Dst: filepath.Join(filepath.Dir(path), newName)})
+	}
+
+	return pairs, nil
+}
+
+// CheckRenamePairs returns an error naming the first collision, which is two
+// items renamed to the same path or a new path taken by an item not renamed.
+func CheckRenamePairs(pairs []RenamePair) error {
+	sources := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		sources[pair.Src] = true
+	}
+
+	targets := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		if other, ok := targets[pair.Dst]; ok {
+			return fmt.Errorf("%s and %s would both be named %s", filepath.Base(other), filepath.Base(pair.Src), filepath.Base(pair.Dst))
+		}
+
+		targets[pair.Dst] = pair.Src
+
+		if sources[pair.Dst] {
+			continue
+		}
+
+		dstInfo, err := os.Lstat(pair.Dst)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// A new name differing only in case on a case insensitive filesystem
+		// finds the item itself.
+		if srcInfo, err := os.Lstat(pair.Src); err != nil || !os.SameFile(srcInfo, dstInfo) {
+			return existsError(pair.Dst)
+		}
+	}
+
+	return nil
+}
+
+// RenameAll renames every item to its new path, failing without renaming
+// anything if two items would get the same path or a new path is taken.
+// Items are first moved to temporary names, so items can swap names. If a
+// rename fails, the items renamed so far are renamed back.
+func RenameAll(pairs []RenamePair) error {
+	if err := CheckRenamePairs(pairs); err != nil {
+		return err
+	}
+
+	var done []RenamePair
+	rollback := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			_ = os.Rename(done[i].Dst, done[i].Src)
+		}
+	}
+
+	stamp := time.Now().UnixNano()
+	temporary := make([]RenamePair, 0, len(pairs))
+	for i, pair := range pairs {
+		if pair.Src == pair.Dst {
+			continue
+		}
+
+		tmp := filepath.Join(filepath.Dir(pair.Src), fmt.Sprintf(".fm-rename-%d-%d", stamp, i))
+		if err := os.Rename(pair.Src, tmp); err != nil {
+			rollback()
+			return err
+		}
+
+		done = append(done, RenamePair{Src: pair.Src, Dst: tmp})
+		temporary = append(temporary, RenamePair{Src: tmp, Dst: pair.Dst})
+	}
+
+	for _, pair := range temporary {
+		if err := os.Rename(pair.Src, pair.Dst); err != nil {
+			rollback()
+			return err
+		}
+
+		done = append(done, pair)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyRenamePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "counter name and extension",
+			pattern: "{n}-{name}{ext}",
+			paths:   []string{"/a/photo.jpg", "/a/notes.txt"},
+			want:    []string{"/a/1-photo.jpg", "/a/2-notes.txt"},
+		},
+		{
+			name:    "counter padded to the last number",
+			pattern: "img{n}{ext}",
+			paths:   []string{"/a/1.png", "/a/2.png", "/a/3.png", "/a/4.png", "/a/5.png", "/a/6.png", "/a/7.png", "/a/8.png", "/a/9.png", "/a/10.png"},
+			want:    []string{"/a/img01.png", "/a/img02.png", "/a/img03.png", "/a/img04.png", "/a/img05.png", "/a/img06.png", "/a/img07.png", "/a/img08.png", "/a/img09.png", "/a/img10.png"},
+		},
+		{
+			name:    "dotfile has no extension",
+			pattern: "{name}.bak{ext}",
+			paths:   []string{"/a/.bashrc"},
+			want:    []string{"/a/.bashrc.bak"},
+		},
+		{
+			name:    "only the last extension",
+			pattern: "{name}-old{ext}",
+			paths:   []string{"/a/archive.tar.gz"},
+			want:    []string{"/a/archive.tar-old.gz"},
+		},
+		{
+			name:    "items keep their directory",
+			pattern: "{name}",
+			paths:   []string{"/a/x.txt", "/b/y.txt"},
+			want:    []string{"/a/x", "/b/y"},
+		},
+		{
+			name:    "empty pattern",
+			pattern: "  ",
+			paths:   []string{"/a/x"},
+			wantErr: true,
+		},
+		{
+			name:    "path separator",
+			pattern: "sub/{name}",
+			paths:   []string{"/a/x"},
+			wantErr: true,
+		},
+		{
+			name:    "parent directory",
+			pattern: "..",
+			paths:   []string{"/a/x"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := ApplyRenamePattern(tt.pattern, tt.paths)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ApplyRenamePattern(%q) = %v, want an error", tt.pattern, pairs)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyRenamePattern(%q) failed: %v", tt.pattern, err)
+			}
+
+			got := make([]string, 0, len(pairs))
+			for i, pair := range pairs {
+				if pair.Src != tt.paths[i] {
+					t.Errorf("pair %d renames %s, want %s", i, pair.Src, tt.paths[i])
+				}
+
+				got = append(got, pair.Dst)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyRenamePattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFiles creates a file in dir for each name, containing its name.
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// dirContents returns the contents of each file in dir by its name.
+func dirContents(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contents[entry.Name()] = string(data)
+	}
+
+	return contents
+}
+
+func TestRenameAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		renames [][2]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "rename",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "c"}},
+			want:    map[string]string{"b": "b", "c": "a"},
+		},
+		{
+			name:    "swap",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "b"}, {"b", "a"}},
+			want:    map[string]string{"a": "b", "b": "a"},
+		},
+		{
+			name:    "rotate",
+			files:   []string{"a", "b", "c"},
+			renames: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+			want:    map[string]string{"a": "c", "b": "a", "c": "b"},
+		},
+		{
+			name:    "unchanged name",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "a"}, {"b", "c"}},
+			want:    map[string]string{"a": "a", "c": "b"},
+		},
+		{
+			name:    "same new name",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "c"}, {"b", "c"}},
+			want:    map[string]string{"a": "a", "b": "b"},
+			wantErr: errors.New("a and b would both be named c"),
+		},
+		{
+			name:    "new name taken",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "b"}},
+			want:    map[string]string{"a": "a", "b": "b"},
+			wantErr: ErrExists,
+		},
+		{
+			name:    "rolled back",
+			files:   []string{"a", "b"},
+			renames: [][2]string{{"a", "c"}, {"missing", "d"}, {"b", "e"}},
+			want:    map[string]string{"a": "a", "b": "b"},
+			wantErr: os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files...)
+
+			pairs := make([]RenamePair, 0, len(tt.renames))
+			for _, rename := range tt.renames {
+				pairs = append(pairs, RenamePair{Src: filepath.Join(dir, rename[0]), Dst: filepath.Join(dir, rename[1])})
+			}
+
+			err := RenameAll(pairs)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("RenameAll failed: %v", err)
+			case tt.wantErr != nil && err == nil:
+				t.Fatalf("RenameAll succeeded, want %v", tt.wantErr)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr) && !strings.Contains(err.Error(), tt.wantErr.Error()):
+				t.Fatalf("RenameAll failed with %v, want %v", err, tt.wantErr)
+			}
+
+			if got := dirContents(t, dir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("directory holds %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
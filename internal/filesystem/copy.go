@@ -0,0 +1,169 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// copyChunkSize is the number of bytes copied between progress reports.
+const copyChunkSize = 256 * 1024
+
+// ProgressFunc is called with the total number of bytes copied so far.
+type ProgressFunc func(copied int64)
+
+// Size returns the combined size of all regular files at or below the path,
+// symlinks are not followed.
+func Size(ctx context.Context, path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+// copier copies files in chunks while keeping track of the progress.
+type copier struct {
+	ctx      context.Context
+	copied   int64
+	progress ProgressFunc
+}
+
+// copyFile copies a single file in chunks, reporting progress after each one.
+func (c *copier) copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := c.ctx.Err(); err != nil {
+			out.Close()
+			return err
+		}
+
+		n, err := io.CopyN(out, in, copyChunkSize)
+		c.copied += n
+		c.progress(c.copied)
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			out.Close()
+			return err
+		}
+	}
+
+	return out.Close()
+}
+
+// copyItem copies a file, symlink or directory recursively.
+func (c *copier) copyItem(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(target, dst)
+	case info.IsDir():
+		if err := os.Mkdir(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := c.copyItem(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return c.copyFile(src, dst, info.Mode())
+	}
+}
+
+// Copy copies a file or directory to a destination which must not exist yet,
+// reporting the number of bytes copied as it goes. If the copy fails or ctx is
+// cancelled, the partially copied destination is removed.
+func Copy(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return existsError(dst)
+	}
+
+	c := copier{ctx: ctx, progress: progress}
+	if err := c.copyItem(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+
+	return nil
+}
+
+// replacementName returns a hidden path next to dst an item is put at before
+// it replaces dst.
+func replacementName(dst string) string {
+	return filepath.Join(
+		filepath.Dir(dst),
+		fmt.Sprintf(".%s.fm-%d", filepath.Base(dst), time.Now().UnixNano()),
+	)
+}
+
+// Replace copies a file or directory over an existing destination. The copy
+// is made next to the destination first, so the destination is only removed
+// once the copy succeeded.
+func Replace(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	tmp := replacementName(dst)
+
+	if err := Copy(ctx, src, tmp, progress); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dst); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
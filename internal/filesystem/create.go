@@ -0,0 +1,96 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrExists is returned when creating an item whose name is already taken.
+var ErrExists = errors.New("already exists")
+
+// existsError returns an error reporting that the path is already taken.
+func existsError(path string) error {
+	return fmt.Errorf("%s %w", filepath.Base(path), ErrExists)
+}
+
+// CreateFile creates a new empty file, failing if the path is already taken.
+func CreateFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return existsError(path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// CreateFromTemplate creates a new file with the content and permissions of
+// a template, failing if the path is already taken.
+func CreateFromTemplate(path, template string) error {
+	src, err := os.Open(template)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if errors.Is(err, os.ErrExist) {
+		return existsError(path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(path)
+
+		return err
+	}
+
+	return dst.Close()
+}
+
+// CreateFileWithContent creates a new file holding the given data, failing
+// if the path is already taken.
+func CreateFileWithContent(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return existsError(path)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// CreateDirectory creates a new directory, failing if the path is already taken.
+func CreateDirectory(path string) error {
+	err := os.Mkdir(path, 0755)
+	if errors.Is(err, os.ErrExist) {
+		return existsError(path)
+	}
+
+	return err
+}
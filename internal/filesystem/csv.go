@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsTable returns true if the file is a CSV or TSV file.
+func IsTable(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv":
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadTable parses the first maxRows rows of a CSV or TSV file, allowing
+// rows with differing numbers of fields. It reports whether rows were left out.
+func ReadTable(path string, maxRows int) ([][]string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if strings.ToLower(filepath.Ext(path)) == ".tsv" {
+		reader.Comma = '\t'
+	}
+
+	var rows [][]string
+	for len(rows) < maxRows {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, false, nil
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		rows = append(rows, row)
+	}
+
+	_, err = reader.Read()
+
+	return rows, !errors.Is(err, io.EOF), nil
+}
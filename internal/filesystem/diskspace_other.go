@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || dragonfly || windows)
+
+package filesystem
+
+// GetDiskSpace returns the disk space of the filesystem containing a path,
+// which is not supported on this platform.
+func GetDiskSpace(path string) (DiskSpace, error) {
+	return DiskSpace{}, ErrDiskSpaceUnsupported
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd || dragonfly
+
+package filesystem
+
+import "syscall"
+
+// GetDiskSpace returns the disk space of the filesystem containing a path,
+// counting only the space available to unprivileged users as available.
+func GetDiskSpace(path string) (DiskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return DiskSpace{}, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+
+	return DiskSpace{
+		Available: int64(uint64(stat.Bavail) * blockSize),
+		Total:     int64(uint64(stat.Blocks) * blockSize),
+	}, nil
+}
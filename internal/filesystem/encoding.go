@@ -0,0 +1,176 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding represents the character encoding of a text file.
+type Encoding int
+
+const (
+	// UTF8 is UTF-8, with or without a byte order mark.
+	UTF8 Encoding = iota
+
+	// UTF16LE is little endian UTF-16 starting with a byte order mark.
+	UTF16LE
+
+	// UTF16BE is big endian UTF-16 starting with a byte order mark.
+	UTF16BE
+
+	// Latin1 is Latin-1, read as Windows-1252.
+	Latin1
+)
+
+var (
+	utf8BOM    = []byte{0xef, 0xbb, 0xbf}
+	utf16LEBOM = []byte{0xff, 0xfe}
+	utf16BEBOM = []byte{0xfe, 0xff}
+)
+
+// windows1252 maps the bytes 0x80 to 0x9f to the characters Windows-1252
+// assigns them, as Latin-1 text is often written with it. Bytes it leaves
+// undefined are zero.
+var windows1252 = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+// String returns the name of an encoding.
+func (e Encoding) String() string {
+	switch e {
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	case Latin1:
+		return "Latin-1"
+	case UTF8:
+	}
+
+	return "UTF-8"
+}
+
+// validUTF8 returns true if data is valid UTF-8, ignoring a rune cut off at
+// the end when the data was cut off.
+func validUTF8(data []byte, cut bool) bool {
+	if utf8.Valid(data) {
+		return true
+	}
+
+	if cut {
+		for i := 1; i < utf8.UTFMax && i < len(data); i++ {
+			if utf8.Valid(data[:len(data)-i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isLatin1Text returns true if every byte is a printable Latin-1 or
+// Windows-1252 character or whitespace.
+func isLatin1Text(data []byte) bool {
+	for _, c := range data {
+		switch {
+		case c == '\t', c == '\n', c == '\v', c == '\f', c == '\r', c == 0x1b:
+		case c < 0x20, c == 0x7f:
+			return false
+		case c >= 0x80 && c < 0xa0 && windows1252[c-0x80] == 0:
+			return false
+		}
+	}
+
+	return true
+}
+
+// detectEncoding returns the encoding of text starting with head, or false
+// if it isn't text. UTF-16 is only recognized by its byte order mark.
+func detectEncoding(head []byte, cut bool) (Encoding, bool) {
+	switch {
+	case bytes.HasPrefix(head, utf16LEBOM):
+		return UTF16LE, true
+	case bytes.HasPrefix(head, utf16BEBOM):
+		return UTF16BE, true
+	case bytes.IndexByte(head, 0) != -1:
+		return UTF8, false
+	case validUTF8(head, cut):
+		return UTF8, true
+	case isLatin1Text(head):
+		return Latin1, true
+	}
+
+	return UTF8, false
+}
+
+// DetectEncoding returns the encoding of a text file based on its first
+// bytes, or false if it isn't text.
+func DetectEncoding(path string) (Encoding, bool) {
+	head, err := readHead(path)
+	if err != nil {
+		return UTF8, false
+	}
+
+	return detectEncoding(head, len(head) == sniffLength)
+}
+
+// DetectTextEncoding returns the encoding of text starting with data, or
+// false if it isn't text. cut reports whether data was cut off.
+func DetectTextEncoding(data []byte, cut bool) (Encoding, bool) {
+	if len(data) > sniffLength {
+		data, cut = data[:sniffLength], true
+	}
+
+	return detectEncoding(data, cut)
+}
+
+// DecodeText converts text in the given encoding to UTF-8, dropping its byte
+// order mark and a character cut off at the end.
+func DecodeText(data []byte, enc Encoding) string {
+	switch enc {
+	case UTF16LE, UTF16BE:
+		return decodeUTF16(data, enc)
+	case Latin1:
+		var sb strings.Builder
+		sb.Grow(len(data))
+
+		for _, c := range data {
+			if c >= 0x80 && c < 0xa0 && windows1252[c-0x80] != 0 {
+				sb.WriteRune(windows1252[c-0x80])
+			} else {
+				sb.WriteRune(rune(c))
+			}
+		}
+
+		return sb.String()
+	case UTF8:
+	}
+
+	return string(bytes.TrimPrefix(data, utf8BOM))
+}
+
+// decodeUTF16 converts UTF-16 text with a byte order mark to UTF-8.
+func decodeUTF16(data []byte, enc Encoding) string {
+	var order binary.ByteOrder = binary.LittleEndian
+	bom := utf16LEBOM
+	if enc == UTF16BE {
+		order, bom = binary.BigEndian, utf16BEBOM
+	}
+
+	data = bytes.TrimPrefix(data, bom)
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+
+	// A high surrogate at the end lost its low surrogate to the size limit.
+	if n := len(units); n > 0 && units[n-1] >= 0xd800 && units[n-1] < 0xdc00 {
+		units = units[:n-1]
+	}
+
+	return string(utf16.Decode(units))
+}
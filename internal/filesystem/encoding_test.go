@@ -0,0 +1,83 @@
+package filesystem
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		cut    bool
+		want   Encoding
+		isText bool
+	}{
+		{name: "ascii", data: []byte("hello\n"), want: UTF8, isText: true},
+		{name: "utf-8", data: []byte("héllo wörld"), want: UTF8, isText: true},
+		{name: "utf-8 bom", data: []byte("\xef\xbb\xbfhello"), want: UTF8, isText: true},
+		{name: "utf-16le bom", data: []byte("\xff\xfeh\x00i\x00"), want: UTF16LE, isText: true},
+		{name: "utf-16be bom", data: []byte("\xfe\xff\x00h\x00i"), want: UTF16BE, isText: true},
+		{name: "utf-16 without bom", data: []byte("h\x00i\x00"), want: UTF8, isText: false},
+		{name: "latin-1", data: []byte("caf\xe9 cr\xe8me"), want: Latin1, isText: true},
+		{name: "windows-1252", data: []byte("\x93quoted\x94 \x80"), want: Latin1, isText: true},
+		{name: "undefined windows-1252 byte", data: []byte("a\x81b"), want: UTF8, isText: false},
+		{name: "latin-1 control character", data: []byte("caf\xe9\x01"), want: UTF8, isText: false},
+		{name: "utf-8 cut off mid rune", data: []byte("caf\xc3"), cut: true, want: UTF8, isText: true},
+		{name: "utf-8 cut off in a 4 byte rune", data: []byte("ok \xf0\x9f\x98"), cut: true, want: UTF8, isText: true},
+		{name: "invalid utf-8 at the end", data: []byte("caf\xc3"), want: Latin1, isText: true},
+		{name: "empty", data: nil, want: UTF8, isText: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isText := detectEncoding(tt.data, tt.cut)
+			if isText != tt.isText || (isText && got != tt.want) {
+				t.Errorf("detectEncoding(%q, %v) = %v, %v, want %v, %v", tt.data, tt.cut, got, isText, tt.want, tt.isText)
+			}
+		})
+	}
+}
+
+func TestDetectEncodingLimit(t *testing.T) {
+	// A rune cut off by the sniff length doesn't make the file binary.
+	path := filepath.Join(t.TempDir(), "text")
+	data := append(bytes.Repeat([]byte("a"), sniffLength-1), "é"...)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, isText := DetectEncoding(path); !isText || got != UTF8 {
+		t.Errorf("DetectEncoding of a rune cut off by the limit = %v, %v, want UTF-8, true", got, isText)
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		enc  Encoding
+		want string
+	}{
+		{name: "utf-8", data: []byte("héllo"), enc: UTF8, want: "héllo"},
+		{name: "utf-8 bom", data: []byte("\xef\xbb\xbfhello"), enc: UTF8, want: "hello"},
+		{name: "latin-1", data: []byte("caf\xe9"), enc: Latin1, want: "café"},
+		{name: "windows-1252", data: []byte("\x93hi\x94 \x80"), enc: Latin1, want: "“hi” €"},
+		{name: "utf-16le", data: []byte("\xff\xfeh\x00\xe9\x00"), enc: UTF16LE, want: "hé"},
+		{name: "utf-16be", data: []byte("\xfe\xff\x00h\x00\xe9"), enc: UTF16BE, want: "hé"},
+		{name: "utf-16le surrogate pair", data: []byte("\xff\xfe\x3d\xd8\x00\xde"), enc: UTF16LE, want: "😀"},
+		{name: "utf-16le cut off in a code unit", data: []byte("\xff\xfeh\x00i"), enc: UTF16LE, want: "h"},
+		{name: "utf-16le cut off after a high surrogate", data: []byte("\xff\xfeh\x00\x3d\xd8"), enc: UTF16LE, want: "h"},
+		{name: "utf-16be only a bom", data: []byte("\xfe\xff"), enc: UTF16BE, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeText(tt.data, tt.enc); got != tt.want {
+				t.Errorf("DecodeText(%q, %v) = %q, want %q", tt.data, tt.enc, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Package filesystem implements the file operations fm performs itself on
+// top of the ones provided by the filetree.
+package filesystem
+
+import "errors"
+
+// ErrTrashUnsupported is returned when moving items to the trash is not
+// supported on the current platform.
+var ErrTrashUnsupported = errors.New("trash is not supported on this platform")
+
+// ErrDiskSpaceUnsupported is returned when reading the disk space of a
+// filesystem is not supported on the current platform.
+var ErrDiskSpaceUnsupported = errors.New("disk space is not supported on this platform")
+
+// ErrMountsUnsupported is returned when listing the mounted filesystems is
+// not supported on the current platform.
+var ErrMountsUnsupported = errors.New("listing mounts is not supported on this platform")
+
+// DiskSpace represents the size of a filesystem and the space available on it.
+type DiskSpace struct {
+	Available int64
+	Total     int64
+}
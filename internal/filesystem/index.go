@@ -0,0 +1,175 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// indexBatchSize is the number of paths passed on at once while indexing.
+const indexBatchSize = 500
+
+// errIndexLimit stops walking a directory once enough files were found.
+var errIndexLimit = errors.New("index limit reached")
+
+// IndexOptions represents which files are listed by IndexFiles.
+type IndexOptions struct {
+	ShowHidden       bool
+	RespectGitignore bool
+	Limit            int
+}
+
+// indexBatcher collects found paths and passes them on in batches, stopping
+// once the limit is reached.
+type indexBatcher struct {
+	found func([]string)
+	batch []string
+	count int
+	limit int
+}
+
+// add adds a path, returning false once the limit is reached.
+func (b *indexBatcher) add(path string) bool {
+	b.batch = append(b.batch, path)
+	b.count++
+
+	if len(b.batch) == indexBatchSize {
+		b.flush()
+	}
+
+	return b.limit <= 0 || b.count < b.limit
+}
+
+// flush passes on the paths collected so far.
+func (b *indexBatcher) flush() {
+	if len(b.batch) > 0 {
+		b.found(b.batch)
+		b.batch = nil
+	}
+}
+
+// isHidden returns true if any element of a relative path starts with a dot.
+func isHidden(path string) bool {
+	for _, name := range strings.Split(filepath.ToSlash(path), "/") {
+		if strings.HasPrefix(name, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IndexFiles lists the files below root as paths relative to it, passing
+// them to found in batches as they are found. Inside a git work tree the
+// files ignored by git are left out when RespectGitignore is set. Hidden
+// files and directories are left out unless ShowHidden is set. Listing
+// stops once ctx is cancelled or Limit files were found.
+func IndexFiles(ctx context.Context, root string, opts IndexOptions, found func([]string)) error {
+	batcher := &indexBatcher{found: found, limit: opts.Limit}
+	defer batcher.flush()
+
+	if opts.RespectGitignore && exec.Command("git", "-C", root, "rev-parse", "--is-inside-work-tree").Run() == nil {
+		return indexGitFiles(ctx, root, opts, batcher)
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil || path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+
+		hidden := strings.HasPrefix(d.Name(), ".")
+		if d.IsDir() {
+			if d.Name() == ".git" || (hidden && !opts.ShowHidden) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if hidden && !opts.ShowHidden {
+			return nil
+		}
+
+		if !batcher.add(rel) {
+			return errIndexLimit
+		}
+
+		return nil
+	})
+	if errors.Is(err, errIndexLimit) {
+		return nil
+	}
+
+	return err
+}
+
+// indexGitFiles lists the tracked and untracked files below root which are
+// not ignored by git. Tracked files deleted from the work tree are left out.
+func indexGitFiles(ctx context.Context, root string, opts IndexOptions, batcher *indexBatcher) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "ls-files", "--cached", "--others", "--exclude-standard", "-z")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	})
+
+	for scanner.Scan() {
+		path := filepath.FromSlash(scanner.Text())
+		if !opts.ShowHidden && isHidden(path) {
+			continue
+		}
+
+		if _, err := os.Lstat(filepath.Join(root, path)); err != nil {
+			continue
+		}
+
+		if !batcher.add(path) {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+
+			return nil
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		return err
+	}
+
+	return scanner.Err()
+}
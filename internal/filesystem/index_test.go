@@ -0,0 +1,47 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIndexFilesLeavesOutDeletedGitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	for _, name := range []string{"kept", "deleted"} {
+		if err := os.WriteFile(filepath.Join(root, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{{"init", "-q"}, {"add", "kept", "deleted"}} {
+		if out, err := exec.Command("git", append([]string{"-C", root}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(root, "deleted")); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err := IndexFiles(context.Background(), root, IndexOptions{RespectGitignore: true}, func(paths []string) {
+		got = append(got, paths...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(got)
+	if want := []string{"kept"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IndexFiles() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/knipferrc/fm/internal/lscolors"
+	"github.com/knipferrc/fm/internal/sortorder"
+	"github.com/knipferrc/fm/internal/strfmt"
+)
+
+// ListDirectory returns a formatted listing of the entries of a directory
+// which aren't hidden, directories first and ending in a slash, followed by
+// the number of entries and how many of them are hidden. Names are sorted in
+// natural order, ignoring case unless caseSensitive is set, and colored by
+// their type with the given palette. Sizes are shown in the given format,
+// right aligned to the widest one.
+func ListDirectory(dir string, palette lscolors.Palette, sizeFormat strfmt.SizeFormat, caseSensitive bool) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+
+		return sortorder.Less(entries[i].Name(), entries[j].Name(), caseSensitive)
+	})
+
+	var (
+		sb        strings.Builder
+		hidden    int
+		infos     []fs.FileInfo
+		sizes     []string
+		sizeWidth int
+	)
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			hidden++
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		size := ""
+		if !entry.IsDir() {
+			size = strfmt.FormatSize(info.Size(), sizeFormat)
+		}
+
+		if len(size) > sizeWidth {
+			sizeWidth = len(size)
+		}
+
+		infos = append(infos, info)
+		sizes = append(sizes, size)
+	}
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for i, info := range infos {
+		name := palette.Paint(info.Name(), info.Mode())
+		if info.IsDir() {
+			name += "/"
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%*s\t%s\t%s\n",
+			info.Mode(),
+			sizeWidth,
+			sizes[i],
+			info.ModTime().Format("2006-01-02 15:04"),
+			name,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	if len(entries) == hidden && hidden > 0 {
+		return fmt.Sprintf("Directory is empty (%d hidden)", hidden), nil
+	}
+
+	if len(entries) == 0 {
+		return "Directory is empty", nil
+	}
+
+	fmt.Fprintf(&sb, "\n%d entries", len(entries)-hidden)
+	if hidden > 0 {
+		fmt.Fprintf(&sb, ", %d hidden", hidden)
+	}
+
+	return sb.String(), nil
+}
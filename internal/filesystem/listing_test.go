@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/knipferrc/fm/internal/lscolors"
+	"github.com/knipferrc/fm/internal/strfmt"
+)
+
+func TestListDirectoryOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		want          []string
+	}{
+		{
+			name: "ignoring case",
+			want: []string{"dir2/", "Dir10/", "a", "B", "file2", "File10"},
+		},
+		{
+			name:          "case sensitive",
+			caseSensitive: true,
+			want:          []string{"Dir10/", "dir2/", "B", "File10", "a", "file2"},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"File10", "file2", "B", "a"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"Dir10", "dir2"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listing, err := ListDirectory(dir, lscolors.Palette{}, strfmt.HumanReadable, tt.caseSensitive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, line := range strings.Split(listing, "\n") {
+				fields := strings.Fields(line)
+				if len(fields) < 4 {
+					continue
+				}
+
+				got = append(got, fields[len(fields)-1])
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListDirectory() order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
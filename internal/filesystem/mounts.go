@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"sort"
+	"strings"
+)
+
+// Mount represents a mounted filesystem, or a drive on Windows.
+type Mount struct {
+	Path   string
+	Device string
+	Type   string
+}
+
+// networkFilesystems are the types of filesystems mounted over the network,
+// which have no device path.
+var networkFilesystems = map[string]bool{
+	"nfs":         true,
+	"nfs4":        true,
+	"cifs":        true,
+	"smbfs":       true,
+	"smb3":        true,
+	"afpfs":       true,
+	"fuse.sshfs":  true,
+	"fuse.rclone": true,
+	"9p":          true,
+}
+
+// imageFilesystems are the types of filesystems backed by a device which
+// hold read only images, such as snaps, rather than user files.
+var imageFilesystems = map[string]bool{
+	"squashfs": true,
+	"overlay":  true,
+}
+
+// isUserMount returns true if a mount holds user files, leaving out virtual
+// filesystems such as proc and tmpfs as well as read only images.
+func isUserMount(m Mount) bool {
+	if m.Path == "/" || networkFilesystems[m.Type] {
+		return true
+	}
+
+	return strings.HasPrefix(m.Device, "/") && !imageFilesystems[m.Type]
+}
+
+// userMounts returns the mounts holding user files sorted by their path,
+// keeping only the last mount on each path as it hides the ones before it.
+func userMounts(mounts []Mount) []Mount {
+	byPath := make(map[string]Mount, len(mounts))
+	for _, m := range mounts {
+		if isUserMount(m) {
+			byPath[m.Path] = m
+		}
+	}
+
+	result := make([]Mount, 0, len(byPath))
+	for _, m := range byPath {
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result
+}
@@ -0,0 +1,41 @@
+//go:build darwin || freebsd || openbsd || netbsd || dragonfly
+
+package filesystem
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// mountLine matches a line of the output of mount, which names the type of
+// the filesystem either first in the parentheses, such as
+// "/dev/disk1s1 on / (apfs, local, journaled)", or before them, such as
+// "/dev/sd0a on / type ffs (local)" on OpenBSD and NetBSD.
+var mountLine = regexp.MustCompile(`^(.+?) on (.+?)(?: type (\S+))? \(([^,)]+)`)
+
+// ListMounts returns the mounted filesystems holding user files, read from
+// the output of mount and sorted by their path.
+func ListMounts() ([]Mount, error) {
+	out, err := exec.Command("mount").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []Mount
+	for _, line := range strings.Split(string(out), "\n") {
+		match := mountLine.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(match[2], "/System/Volumes/") {
+			continue
+		}
+
+		fsType := match[3]
+		if fsType == "" {
+			fsType = match[4]
+		}
+
+		mounts = append(mounts, Mount{Device: match[1], Path: match[2], Type: fsType})
+	}
+
+	return userMounts(mounts), nil
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package filesystem
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ListMounts returns the mounted filesystems holding user files, read from
+// /proc/self/mounts and sorted by their path.
+func ListMounts() ([]Mount, error) {
+	f, err := os.Open("/proc/self/mounts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []Mount
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		mounts = append(mounts, Mount{
+			Device: unescapeMountField(fields[0]),
+			Path:   unescapeMountField(fields[1]),
+			Type:   fields[2],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return userMounts(mounts), nil
+}
+
+// unescapeMountField replaces the octal escapes /proc/self/mounts uses for
+// spaces, tabs, newlines and backslashes in a field.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if c, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(c))
+				i += 3
+
+				continue
+			}
+		}
+
+		sb.WriteByte(field[i])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly || windows)
+
+package filesystem
+
+// ListMounts returns the mounted filesystems, which is not supported on this
+// platform.
+func ListMounts() ([]Mount, error) {
+	return nil, ErrMountsUnsupported
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package filesystem
+
+import "syscall"
+
+// getLogicalDrives is the kernel32 function reporting the available drives.
+var getLogicalDrives = syscall.NewLazyDLL("kernel32.dll").NewProc("GetLogicalDrives")
+
+// ListMounts returns the root directories of the available drives.
+func ListMounts() ([]Mount, error) {
+	ret, _, err := getLogicalDrives.Call()
+	if ret == 0 {
+		return nil, err
+	}
+
+	var mounts []Mount
+	for i := 0; i < 26; i++ {
+		if ret&(1<<uint(i)) == 0 {
+			continue
+		}
+
+		drive := string(rune('A'+i)) + ":"
+		mounts = append(mounts, Mount{Path: drive + `\`, Device: drive, Type: "drive"})
+	}
+
+	return mounts, nil
+}
@@ -0,0 +1,42 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Move moves a file or directory to a destination which must not exist yet.
+// Moving to another filesystem falls back to copying the item, reporting the
+// number of bytes copied as it goes, and removing it once it was copied.
+func Move(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	err := Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := Copy(ctx, src, dst, progress); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(src)
+}
+
+// ReplaceByMove moves a file or directory over an existing destination. The
+// item is moved next to the destination first, so the destination is only
+// removed once the move succeeded, and the item is moved back if it can't be.
+func ReplaceByMove(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	tmp := replacementName(dst)
+
+	if err := Move(ctx, src, tmp, progress); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dst); err != nil {
+		_ = Move(context.Background(), tmp, src, func(int64) {})
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
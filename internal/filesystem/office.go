@@ -0,0 +1,100 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// maxOfficePropertiesBytes is the most read of the XML files holding the
+// properties of a document, which are small.
+const maxOfficePropertiesBytes = 1024 * 1024
+
+// ErrNotOfficeDocument is returned when a zip archive named like an Office
+// document doesn't hold the properties of one.
+var ErrNotOfficeDocument = errors.New("not an Office document")
+
+// officeExtensions are the extensions of Office Open XML documents.
+var officeExtensions = []string{".docx", ".xlsx", ".pptx"}
+
+// OfficeProperties represents the properties of an Office Open XML document
+// stored in docProps/core.xml and docProps/app.xml. Counts which aren't
+// stored, such as the words of a spreadsheet, are 0.
+type OfficeProperties struct {
+	Title          string `xml:"title"`
+	Subject        string `xml:"subject"`
+	Creator        string `xml:"creator"`
+	Keywords       string `xml:"keywords"`
+	Description    string `xml:"description"`
+	LastModifiedBy string `xml:"lastModifiedBy"`
+	Created        string `xml:"created"`
+	Modified       string `xml:"modified"`
+	Application    string `xml:"Application"`
+	Pages          int    `xml:"Pages"`
+	Words          int    `xml:"Words"`
+	Characters     int    `xml:"Characters"`
+	Paragraphs     int    `xml:"Paragraphs"`
+	Slides         int    `xml:"Slides"`
+}
+
+// IsOfficeDocument returns true if the file name is that of an Office Open
+// XML document, which is a zip archive of XML files.
+func IsOfficeDocument(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, officeExt := range officeExtensions {
+		if ext == officeExt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadOfficeProperties reads the properties of an Office Open XML document,
+// such as its title, author and word count, without extracting it.
+func ReadOfficeProperties(path string) (OfficeProperties, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return OfficeProperties{}, err
+	}
+	defer reader.Close()
+
+	var (
+		props OfficeProperties
+		found bool
+	)
+
+	for _, file := range reader.File {
+		if file.Name != "docProps/core.xml" && file.Name != "docProps/app.xml" {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return OfficeProperties{}, err
+		}
+
+		data, _, err := readLimitedFrom(rc, maxOfficePropertiesBytes)
+		rc.Close()
+
+		if err != nil {
+			return OfficeProperties{}, err
+		}
+
+		// Both files are decoded into the same struct, each filling in the
+		// fields it holds.
+		if err := xml.Unmarshal(data, &props); err != nil {
+			return OfficeProperties{}, err
+		}
+
+		found = true
+	}
+
+	if !found {
+		return OfficeProperties{}, ErrNotOfficeDocument
+	}
+
+	return props, nil
+}
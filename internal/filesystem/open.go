@@ -0,0 +1,31 @@
+package filesystem
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// OpenWithDefaultApp opens a file in the application the system associates
+// with it, without waiting for the application to exit.
+func OpenWithDefaultApp(path string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath expands a leading ~ and environment variables in a path and
+// returns it as an absolute path.
+func ExpandPath(path string) (string, error) {
+	return ExpandPathFrom(".", path)
+}
+
+// ExpandPathFrom expands a path like ExpandPath, resolving a relative path
+// against base instead of the working directory.
+func ExpandPathFrom(base, path string) (string, error) {
+	path = os.ExpandEnv(strings.TrimSpace(path))
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+
+	return filepath.Abs(path)
+}
+
+// ValidateDirectory returns an error if the path does not exist or is not a directory.
+func ValidateDirectory(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+
+	return nil
+}
+
+// directoryMatches returns the last component of a path along with the
+// sorted names of the directories next to it which start with it. Hidden
+// directories are left out unless the last component starts with a dot.
+func directoryMatches(path string) (string, []string) {
+	expanded := os.ExpandEnv(path)
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil
+		}
+
+		expanded = home + strings.TrimPrefix(expanded, "~")
+	}
+
+	dir, prefix := filepath.Split(expanded)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		if strings.HasPrefix(entry.Name(), ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+
+		matches = append(matches, entry.Name())
+	}
+
+	return prefix, matches
+}
+
+// CompleteDirectory completes the last component of a path to the longest
+// prefix shared by the directories it matches, leaving the path unchanged
+// if nothing matches. A path completed to a single directory ends in a separator.
+func CompleteDirectory(path string) string {
+	prefix, matches := directoryMatches(path)
+	if len(matches) == 0 {
+		return path
+	}
+
+	completion := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, completion) {
+			completion = completion[:len(completion)-1]
+		}
+	}
+
+	if len(matches) == 1 {
+		completion += string(filepath.Separator)
+	}
+
+	return path + strings.TrimPrefix(completion, prefix)
+}
+
+// DirectoryCompletions returns the path completed to each directory it
+// matches in turn, each ending in a separator.
+func DirectoryCompletions(path string) []string {
+	prefix, matches := directoryMatches(path)
+
+	completions := make([]string, len(matches))
+	for i, match := range matches {
+		completions[i] = path + strings.TrimPrefix(match, prefix) + string(filepath.Separator)
+	}
+
+	return completions
+}
@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ReadPDFText extracts the plain text of the first maxPages pages of a PDF,
+// noting how many pages were left out. Extraction stops once ctx is done.
+func ReadPDFText(ctx context.Context, path string, maxPages int) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to read %s: %v", path, r)
+		}
+	}()
+
+	f, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	numPages := reader.NumPage()
+	pages := numPages
+	if pages > maxPages {
+		pages = maxPages
+	}
+
+	var b strings.Builder
+	fonts := make(map[string]*pdf.Font)
+
+	for i := 1; i <= pages; i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+
+		content, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(content)
+		b.WriteString("\n\n")
+	}
+
+	if numPages > pages {
+		fmt.Fprintf(&b, "... %d more pages", numPages-pages)
+	}
+
+	return b.String(), nil
+}
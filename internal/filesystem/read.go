@@ -0,0 +1,50 @@
+package filesystem
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// ReadLimited reads up to limit bytes from the start of a file, reporting
+// whether the file is longer. A rune cut off by the limit is dropped. A limit
+// of zero or less reads the whole file.
+func ReadLimited(path string, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		data, err := os.ReadFile(path)
+
+		return data, false, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(data)) <= limit {
+		return data, false, nil
+	}
+
+	return trimCutRune(data[:limit]), true, nil
+}
+
+// trimCutRune drops a rune cut off at the end of data.
+func trimCutRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				data = data[:len(data)-i]
+			}
+
+			break
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,26 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+)
+
+// Rename renames an item, failing if the new path is already taken by another item.
+func Rename(src, dst string) error {
+	if dstInfo, err := os.Lstat(dst); err == nil {
+		srcInfo, err := os.Lstat(src)
+		if err != nil {
+			return err
+		}
+
+		// Renaming to a name differing only in case on a case insensitive
+		// filesystem finds the item itself.
+		if !os.SameFile(srcInfo, dstInfo) {
+			return existsError(dst)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return os.Rename(src, dst)
+}
@@ -0,0 +1,75 @@
+package filesystem
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Summary describes the entries of a directory.
+type Summary struct {
+	Files       int
+	Directories int
+	Size        int64
+}
+
+// Summarize counts the files and subdirectories directly inside a directory
+// and adds up their sizes, including everything below the subdirectories.
+// Entries that can't be read are skipped.
+func Summarize(ctx context.Context, dir string) (Summary, error) {
+	var summary Summary
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return summary, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			summary.Directories++
+		} else {
+			summary.Files++
+		}
+	}
+
+	err = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		summary.Size += info.Size()
+
+		return nil
+	})
+
+	return summary, err
+}
+
+// CountHidden counts the entries directly inside a directory whose name
+// starts with a dot.
+func CountHidden(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	hidden := 0
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			hidden++
+		}
+	}
+
+	return hidden, nil
+}
@@ -0,0 +1,29 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SymlinkTarget returns the target of a symlink and the path it resolves to,
+// which is empty if it doesn't resolve to an existing file. Symlink loops are
+// reported as not resolving. The returned ok is false if the path is not a
+// symlink.
+func SymlinkTarget(path string) (target string, resolved string, ok bool) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", "", false
+	}
+
+	target, err = os.Readlink(path)
+	if err != nil {
+		return "", "", true
+	}
+
+	resolved, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return target, "", true
+	}
+
+	return target, resolved, true
+}
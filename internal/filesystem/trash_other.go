@@ -0,0 +1,18 @@
+//go:build !(linux || freebsd || openbsd || netbsd || dragonfly)
+
+package filesystem
+
+// TrashSupported is true if items can be moved to the trash on this platform.
+const TrashSupported = false
+
+// TrashFile moves a file or directory to the trash, which is not supported on
+// this platform.
+func TrashFile(path string) (string, error) {
+	return "", ErrTrashUnsupported
+}
+
+// RestoreFromTrash moves an item in the trash back to its original path,
+// which is not supported on this platform.
+func RestoreFromTrash(trashPath, originalPath string) error {
+	return ErrTrashUnsupported
+}
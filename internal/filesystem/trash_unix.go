@@ -0,0 +1,197 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// TrashSupported is true if items can be moved to the trash on this platform.
+const TrashSupported = true
+
+// trashDir returns the home trash directory as defined by the freedesktop trash spec.
+func trashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// createTrashInfo reserves a unique name in the trash by creating its
+// .trashinfo file, returning the name that was reserved.
+func createTrashInfo(infoDir, name, originalPath string) (string, error) {
+	contents := fmt.Sprintf(
+		"[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: originalPath}).EscapedPath(),
+		time.Now().Format("2006-01-02T15:04:05"),
+	)
+
+	ext := filepath.Ext(name)
+	base := name[:len(name)-len(ext)]
+	trashName := name
+
+	for i := 1; ; i++ {
+		f, err := os.OpenFile(
+			filepath.Join(infoDir, trashName+".trashinfo"),
+			os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+			0600,
+		)
+		if errors.Is(err, os.ErrExist) {
+			trashName = fmt.Sprintf("%s.%d%s", base, i, ext)
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if _, err = f.WriteString(contents); err != nil {
+			f.Close()
+			return "", err
+		}
+
+		return trashName, f.Close()
+	}
+}
+
+// deviceOf returns the device the item at path resides on.
+func deviceOf(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("unable to read the device of %s", path)
+	}
+
+	return uint64(stat.Dev), nil
+}
+
+// topDir returns the mount point of the filesystem the directory resides on,
+// the topmost directory above it on the same device.
+func topDir(dir string) (string, error) {
+	dev, err := deviceOf(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir, nil
+		}
+
+		parentDev, err := deviceOf(parent)
+		if err != nil || parentDev != dev {
+			return dir, nil
+		}
+
+		dir = parent
+	}
+}
+
+// trashDirFor returns the trash an item is moved to. Items on the filesystem
+// of the home trash go there, others go to the $topdir/.Trash-$uid directory
+// of their own filesystem so that trashing them doesn't copy them.
+func trashDirFor(path, homeTrash string) (string, error) {
+	itemDev, err := deviceOf(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+
+	homeDev, err := deviceOf(homeTrash)
+	if err != nil || itemDev == homeDev {
+		return homeTrash, err
+	}
+
+	top, err := topDir(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(top, fmt.Sprintf(".Trash-%d", os.Getuid())), nil
+}
+
+// makeTrashDirs creates the files and info directories of a trash.
+func makeTrashDirs(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0700); err != nil {
+		return err
+	}
+
+	return os.MkdirAll(filepath.Join(dir, "info"), 0700)
+}
+
+// TrashFile moves a file or directory to the trash, writing the metadata
+// needed to restore it to its original location. Items on another filesystem
+// than the home trash are moved to the trash of their own filesystem, or
+// copied to the home trash if that can't be created. It returns the path the
+// item was moved to in the trash.
+func TrashFile(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	homeTrash, err := trashDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := makeTrashDirs(homeTrash); err != nil {
+		return "", err
+	}
+
+	dir, err := trashDirFor(absPath, homeTrash)
+	if err != nil {
+		return "", err
+	}
+
+	if dir != homeTrash {
+		if err := makeTrashDirs(dir); err != nil {
+			dir = homeTrash
+		}
+	}
+
+	infoDir := filepath.Join(dir, "info")
+
+	trashName, err := createTrashInfo(infoDir, filepath.Base(absPath), absPath)
+	if err != nil {
+		return "", err
+	}
+
+	trashPath := filepath.Join(dir, "files", trashName)
+	if err := Move(context.Background(), absPath, trashPath, func(int64) {}); err != nil {
+		_ = os.Remove(filepath.Join(infoDir, trashName+".trashinfo"))
+		return "", err
+	}
+
+	return trashPath, nil
+}
+
+// RestoreFromTrash moves the item at the given path in the trash back to its
+// original path, failing if that path has been taken in the meantime.
+func RestoreFromTrash(trashPath, originalPath string) error {
+	if err := Move(context.Background(), trashPath, originalPath, func(int64) {}); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filepath.Dir(trashPath))
+
+	return os.Remove(filepath.Join(dir, "info", filepath.Base(trashPath)+".trashinfo"))
+}
@@ -0,0 +1,306 @@
+// Package finder implements a bubble which fuzzy finds files in a list of
+// paths that is filled in while it is shown.
+package finder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/sahilm/fuzzy"
+)
+
+// maxWidth is the maximum width of the overlay, including its border.
+const maxWidth = 100
+
+// TitleColor represents the colors of the title.
+type TitleColor struct {
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+}
+
+// Bubble represents the properties of a finder bubble.
+type Bubble struct {
+	input             textinput.Model
+	title             string
+	titleColor        TitleColor
+	borderColor       lipgloss.AdaptiveColor
+	selectedItemColor lipgloss.AdaptiveColor
+	paths             []string
+	matches           []string
+	query             string
+	cursor            int
+	offset            int
+	scrollOff         int
+	indexing          bool
+	visible           bool
+	width             int
+	height            int
+	up                key.Binding
+	down              key.Binding
+}
+
+// New creates a new instance of a finder.
+func New(title string, titleColor TitleColor, borderColor, selectedItemColor lipgloss.AdaptiveColor) Bubble {
+	input := textinput.New()
+	input.Prompt = "> "
+
+	return Bubble{
+		input:             input,
+		title:             title,
+		titleColor:        titleColor,
+		borderColor:       borderColor,
+		selectedItemColor: selectedItemColor,
+		up:                key.NewBinding(key.WithKeys("up", "ctrl+k", "ctrl+p")),
+		down:              key.NewBinding(key.WithKeys("down", "ctrl+j", "ctrl+n")),
+	}
+}
+
+// style returns the style of the box around the overlay.
+func (b Bubble) style() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(b.borderColor)
+}
+
+// boxWidth returns the width of the overlay, including its border.
+func (b Bubble) boxWidth() int {
+	if b.width-2 < maxWidth {
+		return b.width - 2
+	}
+
+	return maxWidth
+}
+
+// contentWidth returns the width available to the input and the matches.
+func (b Bubble) contentWidth() int {
+	return b.boxWidth() - b.style().GetHorizontalFrameSize()
+}
+
+// listHeight returns the number of matches which fit below the title, the
+// input and the hint.
+func (b Bubble) listHeight() int {
+	return b.height - 2 - b.style().GetVerticalFrameSize() - 4
+}
+
+// fixOffset scrolls the list so that the cursor is visible, keeping up to
+// scrollOff matches visible above and below it.
+func (b *Bubble) fixOffset() {
+	height := b.listHeight()
+	if height < 1 {
+		return
+	}
+
+	margin := b.scrollOff
+	if margin > (height-1)/2 {
+		margin = (height - 1) / 2
+	}
+
+	if b.cursor-margin < b.offset {
+		b.offset = b.cursor - margin
+	}
+
+	if b.cursor+margin >= b.offset+height {
+		b.offset = b.cursor + margin - height + 1
+	}
+
+	if maxOffset := len(b.matches) - height; b.offset > maxOffset {
+		b.offset = maxOffset
+	}
+
+	if b.offset < 0 {
+		b.offset = 0
+	}
+}
+
+// SetScrollOff sets the number of matches kept visible above and below the
+// cursor when scrolling.
+func (b *Bubble) SetScrollOff(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+
+	b.scrollOff = lines
+	b.fixOffset()
+}
+
+// filter matches the paths against the query, keeping the cursor on the
+// best match whenever the query changes.
+func (b *Bubble) filter() {
+	queryChanged := b.query != b.input.Value()
+	b.query = b.input.Value()
+
+	if b.query == "" {
+		b.matches = b.paths
+	} else {
+		results := fuzzy.Find(b.query, b.paths)
+		b.matches = make([]string, len(results))
+		for i, result := range results {
+			b.matches[i] = result.Str
+		}
+	}
+
+	if queryChanged || b.cursor >= len(b.matches) {
+		b.cursor = 0
+		b.offset = 0
+	}
+
+	b.fixOffset()
+}
+
+// Show shows the overlay with an empty query and no paths.
+func (b *Bubble) Show() {
+	b.visible = true
+	b.indexing = true
+	b.paths = nil
+	b.matches = nil
+	b.query = ""
+	b.cursor = 0
+	b.offset = 0
+	b.input.SetValue("")
+	b.input.Focus()
+}
+
+// Hide hides the overlay.
+func (b *Bubble) Hide() {
+	b.visible = false
+	b.input.Blur()
+}
+
+// Visible returns true if the overlay is shown.
+func (b Bubble) Visible() bool {
+	return b.visible
+}
+
+// AddPaths adds paths to find in, marking the list complete once done is true.
+func (b *Bubble) AddPaths(paths []string, done bool) {
+	b.paths = append(b.paths, paths...)
+	b.indexing = !done
+	b.filter()
+}
+
+// SelectedPath returns the match under the cursor.
+func (b Bubble) SelectedPath() (string, bool) {
+	if len(b.matches) == 0 {
+		return "", false
+	}
+
+	return b.matches[b.cursor], true
+}
+
+// SetSize sets the size of the area the overlay is centered in.
+func (b *Bubble) SetSize(w, h int) {
+	b.width = w
+	b.height = h
+	b.input.Width = b.contentWidth() - lipgloss.Width(b.input.Prompt) - 1
+	b.fixOffset()
+}
+
+// SetBorderColor sets the current color of the border.
+func (b *Bubble) SetBorderColor(color lipgloss.AdaptiveColor) {
+	b.borderColor = color
+}
+
+// SetTitleColor sets the color of the title.
+func (b *Bubble) SetTitleColor(color TitleColor) {
+	b.titleColor = color
+}
+
+// SetSelectedItemColor sets the color of the match under the cursor.
+func (b *Bubble) SetSelectedItemColor(color lipgloss.AdaptiveColor) {
+	b.selectedItemColor = color
+}
+
+// Update handles moving the cursor and editing the query while the overlay is shown.
+func (b Bubble) Update(msg tea.Msg) (Bubble, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if !b.visible {
+		return b, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, b.up):
+			if b.cursor > 0 {
+				b.cursor--
+			}
+
+			b.fixOffset()
+
+			return b, nil
+		case key.Matches(msg, b.down):
+			if b.cursor < len(b.matches)-1 {
+				b.cursor++
+			}
+
+			b.fixOffset()
+
+			return b, nil
+		}
+	}
+
+	b.input, cmd = b.input.Update(msg)
+	if b.input.Value() != b.query {
+		b.filter()
+	}
+
+	return b, cmd
+}
+
+// View returns a string representation of the overlay centered in its area.
+func (b Bubble) View() string {
+	style := b.style()
+	width := b.contentWidth()
+	height := b.listHeight()
+	if width < 1 || height < 1 {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Background(b.titleColor.Background).
+		Foreground(b.titleColor.Foreground).
+		Padding(0, 1).
+		Render(truncate.StringWithTail(b.title, uint(width-2), "..."))
+
+	lines := []string{title, "", b.input.View()}
+
+	end := b.offset + height
+	if end > len(b.matches) {
+		end = len(b.matches)
+	}
+
+	for i := b.offset; i < end; i++ {
+		line := truncate.StringWithTail(b.matches[i], uint(width), "...")
+		if i == b.cursor {
+			line = lipgloss.NewStyle().Foreground(b.selectedItemColor).Bold(true).Render(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	for i := end - b.offset; i < height; i++ {
+		lines = append(lines, "")
+	}
+
+	status := fmt.Sprintf("%d/%d", len(b.matches), len(b.paths))
+	if b.indexing {
+		status = fmt.Sprintf("%s, indexing...", status)
+	}
+
+	hint := lipgloss.NewStyle().
+		Faint(true).
+		Render(truncate.StringWithTail(fmt.Sprintf("%s • enter open • esc close", status), uint(width), "..."))
+
+	lines = append(lines, hint)
+
+	box := style.Width(b.boxWidth() - style.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(b.width, b.height, lipgloss.Center, lipgloss.Center, box)
+}
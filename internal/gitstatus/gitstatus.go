@@ -0,0 +1,92 @@
+// Package gitstatus implements reading the git status of the entries of a directory.
+package gitstatus
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepository is returned for directories outside of a git working tree.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Status maps the absolute paths of the entries of a directory to their git status.
+type Status map[string]string
+
+// describe returns a short description of a porcelain status code.
+func describe(code string) string {
+	switch {
+	case code == "??":
+		return "untracked"
+	case strings.Contains(code, "U") || code == "AA" || code == "DD":
+		return "conflicted"
+	case code[1] == 'M' || code[1] == 'D':
+		return "modified"
+	case code[0] == 'A':
+		return "added"
+	case code[0] == 'R':
+		return "renamed"
+	case code[0] == 'D':
+		return "deleted"
+	default:
+		return "staged"
+	}
+}
+
+// Read returns the git status of the entries of a directory. Entries below a
+// subdirectory mark the subdirectory as modified.
+func Read(dir string) (Status, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, ErrNotRepository
+	}
+
+	root := strings.TrimSpace(string(out))
+
+	// git reports paths below the resolved root of the working tree.
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = exec.Command("git", "-C", dir, "status", "--porcelain=v1", "-z", "--", ".").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(Status)
+	entries := bytes.Split(out, []byte{0})
+
+	for i := 0; i < len(entries); i++ {
+		entry := string(entries[i])
+		if len(entry) < 4 {
+			continue
+		}
+
+		code, path := entry[:2], strings.TrimSuffix(entry[3:], "/")
+
+		// The original path of a rename follows as a separate entry.
+		if code[0] == 'R' || code[0] == 'C' {
+			i++
+		}
+
+		rel, err := filepath.Rel(resolvedDir, filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		parts := strings.SplitN(rel, string(filepath.Separator), 2)
+		entryPath := filepath.Join(dir, parts[0])
+
+		switch {
+		case len(parts) == 1:
+			status[entryPath] = describe(code)
+		case status[entryPath] == "":
+			status[entryPath] = "modified"
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,117 @@
+// Package iconset implements the glyphs fm draws itself, for terminals with
+// a nerd font as well as for those without one.
+package iconset
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knipferrc/teacup/icons"
+)
+
+// Set represents a set of glyphs.
+type Set int
+
+const (
+	// Nerd uses nerd font glyphs, which need a patched font.
+	Nerd Set = iota
+
+	// Emoji uses emoji, which most terminal fonts can show.
+	Emoji
+
+	// ASCII uses plain characters only.
+	ASCII
+
+	// None leaves out icons.
+	None
+)
+
+// glyph represents the glyphs of a kind of item in each set.
+type glyph struct {
+	nerd  string
+	emoji string
+	ascii string
+}
+
+var (
+	directoryGlyph = glyph{nerd: icons.IconDef["dir"].GetGlyph(), emoji: "📁", ascii: "/"}
+	fileGlyph      = glyph{nerd: "", emoji: "📄", ascii: "-"}
+)
+
+// names maps the names of the sets in the config to the sets.
+var names = map[string]Set{
+	"nerd":  Nerd,
+	"emoji": Emoji,
+	"ascii": ASCII,
+	"none":  None,
+}
+
+// Parse returns the set with the given name, detecting the set the terminal
+// can show for "auto" or an empty name.
+func Parse(name string) (Set, error) {
+	if name == "" || strings.EqualFold(name, "auto") {
+		return Detect(), nil
+	}
+
+	if set, ok := names[strings.ToLower(name)]; ok {
+		return set, nil
+	}
+
+	return Detect(), fmt.Errorf("unknown icon set %q", name)
+}
+
+// Detect guesses the set the terminal can show. Whether a nerd font is
+// installed can't be detected, so it is assumed unless the terminal is the
+// Linux console or the locale isn't UTF-8, in which case ASCII is used.
+func Detect() Set {
+	if os.Getenv("TERM") == "linux" {
+		return ASCII
+	}
+
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+
+	if locale != "" && !strings.Contains(strings.ToUpper(locale), "UTF-8") && !strings.Contains(strings.ToUpper(locale), "UTF8") {
+		return ASCII
+	}
+
+	return Nerd
+}
+
+// pick returns the glyph of the set.
+func (s Set) pick(g glyph) string {
+	switch s {
+	case Nerd:
+		return g.nerd
+	case Emoji:
+		return g.emoji
+	case ASCII:
+		return g.ascii
+	case None:
+	}
+
+	return ""
+}
+
+// Directory returns the glyph of a directory, which is empty for None.
+func (s Set) Directory() string {
+	return s.pick(directoryGlyph)
+}
+
+// File returns the glyph of a file, which is empty for None.
+func (s Set) File() string {
+	return s.pick(fileGlyph)
+}
+
+// TreeIcons returns true if the filetree can show its icons, which are
+// always nerd font glyphs.
+func (s Set) TreeIcons() bool {
+	return s == Nerd
+}
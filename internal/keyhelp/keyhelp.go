@@ -0,0 +1,213 @@
+// Package keyhelp implements a bubble which renders keybindings grouped by
+// category in a scrollable overlay.
+package keyhelp
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// maxWidth is the maximum width of the overlay, including its border.
+	maxWidth = 80
+
+	// maxKeyWidth is the maximum width of the key column as a fraction of
+	// the content width.
+	maxKeyWidth = 3
+)
+
+// Entry represents a single keybinding.
+type Entry struct {
+	Key         string
+	Description string
+}
+
+// Group represents a category of keybindings.
+type Group struct {
+	Title   string
+	Entries []Entry
+}
+
+// TitleColor represents the colors of the title.
+type TitleColor struct {
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+}
+
+// Bubble represents the properties of a keyhelp bubble.
+type Bubble struct {
+	viewport     viewport.Model
+	title        string
+	groups       []Group
+	titleColor   TitleColor
+	borderColor  lipgloss.AdaptiveColor
+	headingColor lipgloss.AdaptiveColor
+	visible      bool
+	width        int
+	height       int
+}
+
+// New creates a new instance of a keyhelp overlay.
+func New(title string, titleColor TitleColor, borderColor, headingColor lipgloss.AdaptiveColor) Bubble {
+	return Bubble{
+		viewport:     viewport.New(0, 0),
+		title:        title,
+		titleColor:   titleColor,
+		borderColor:  borderColor,
+		headingColor: headingColor,
+	}
+}
+
+// style returns the style of the box around the overlay.
+func (b Bubble) style() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(b.borderColor)
+}
+
+// boxWidth returns the width of the overlay, including its border.
+func (b Bubble) boxWidth() int {
+	if b.width-2 < maxWidth {
+		return b.width - 2
+	}
+
+	return maxWidth
+}
+
+// contentWidth returns the width available to the keybindings.
+func (b Bubble) contentWidth() int {
+	return b.boxWidth() - b.style().GetHorizontalFrameSize()
+}
+
+// render lays out the groups to fit the current width and sets them as the
+// content of the viewport.
+func (b *Bubble) render() {
+	width := b.contentWidth()
+	if width < 1 {
+		b.viewport.SetContent("")
+		return
+	}
+
+	keyWidth := 0
+	for _, group := range b.groups {
+		for _, entry := range group.Entries {
+			if w := lipgloss.Width(entry.Key); w > keyWidth {
+				keyWidth = w
+			}
+		}
+	}
+
+	if keyWidth > width/maxKeyWidth {
+		keyWidth = width / maxKeyWidth
+	}
+
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(b.headingColor)
+	keyStyle := lipgloss.NewStyle().Bold(true).Width(keyWidth).MarginRight(2)
+	descriptionStyle := lipgloss.NewStyle().Width(width - keyWidth - 2)
+
+	var sections []string
+	for _, group := range b.groups {
+		lines := []string{headingStyle.Render(truncate.StringWithTail(group.Title, uint(width), "..."))}
+		for _, entry := range group.Entries {
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+				keyStyle.Render(entry.Key),
+				descriptionStyle.Render(entry.Description),
+			))
+		}
+
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	b.viewport.SetContent(strings.Join(sections, "\n\n"))
+}
+
+// SetGroups sets the keybindings to show.
+func (b *Bubble) SetGroups(groups []Group) {
+	b.groups = groups
+	b.render()
+}
+
+// Show shows the overlay scrolled to the top.
+func (b *Bubble) Show() {
+	b.visible = true
+	b.viewport.GotoTop()
+}
+
+// Hide hides the overlay.
+func (b *Bubble) Hide() {
+	b.visible = false
+}
+
+// Visible returns true if the overlay is shown.
+func (b Bubble) Visible() bool {
+	return b.visible
+}
+
+// SetSize sets the size of the area the overlay is centered in.
+func (b *Bubble) SetSize(w, h int) {
+	b.width = w
+	b.height = h
+
+	// The title, the blank line below it and the hint take up three lines.
+	b.viewport.Width = b.contentWidth()
+	b.viewport.Height = h - 2 - b.style().GetVerticalFrameSize() - 3
+	b.render()
+}
+
+// SetBorderColor sets the current color of the border.
+func (b *Bubble) SetBorderColor(color lipgloss.AdaptiveColor) {
+	b.borderColor = color
+}
+
+// SetTitleColor sets the color of the title.
+func (b *Bubble) SetTitleColor(color TitleColor) {
+	b.titleColor = color
+}
+
+// SetHeadingColor sets the color of the group headings.
+func (b *Bubble) SetHeadingColor(color lipgloss.AdaptiveColor) {
+	b.headingColor = color
+	b.render()
+}
+
+// Update handles scrolling the overlay while it is shown.
+func (b Bubble) Update(msg tea.Msg) (Bubble, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if b.visible {
+		b.viewport, cmd = b.viewport.Update(msg)
+	}
+
+	return b, cmd
+}
+
+// View returns a string representation of the overlay centered in its area.
+func (b Bubble) View() string {
+	style := b.style()
+	width := b.contentWidth()
+	if width < 1 || b.viewport.Height < 1 {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Background(b.titleColor.Background).
+		Foreground(b.titleColor.Foreground).
+		Padding(0, 1).
+		Render(truncate.StringWithTail(b.title, uint(width-2), "..."))
+
+	hint := lipgloss.NewStyle().
+		Faint(true).
+		Render(truncate.StringWithTail("j/k scroll • esc close", uint(width), "..."))
+
+	box := style.Width(b.boxWidth() - style.GetHorizontalBorderSize()).Render(
+		lipgloss.JoinVertical(lipgloss.Left, title, "", b.viewport.View(), hint),
+	)
+
+	return lipgloss.Place(b.width, b.height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,156 @@
+// Package logger writes leveled log lines with key value pairs to a file.
+// Nothing is written until a file is opened.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level represents the severity of a log line.
+type Level int
+
+const (
+	// LevelDebug is used for details such as key presses.
+	LevelDebug Level = iota
+
+	// LevelInfo is used for file operations and status messages.
+	LevelInfo
+
+	// LevelWarn is used for problems fm recovers from, such as invalid keybindings.
+	LevelWarn
+
+	// LevelError is used for operations which failed.
+	LevelError
+)
+
+// levelNames maps the levels to their name in the config and the log file.
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+var (
+	mu       sync.Mutex
+	file     *os.File
+	minLevel = LevelInfo
+)
+
+// String returns the name of a level.
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel returns the level with the given name, which defaults to info.
+func ParseLevel(name string) (Level, error) {
+	if name == "" {
+		return LevelInfo, nil
+	}
+
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Open appends the log lines of at least the given level to the file at
+// path. Lines of the standard logger are written to it as well.
+func Open(path string, level Level) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	file = f
+	minLevel = level
+	log.SetOutput(f)
+
+	return nil
+}
+
+// Close closes the log file, after which nothing is written anymore.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil {
+		return nil
+	}
+
+	log.SetOutput(os.Stderr)
+	err := file.Close()
+	file = nil
+
+	return err
+}
+
+// Debug writes a debug line with the given key value pairs.
+func Debug(msg string, keyvals ...interface{}) {
+	write(LevelDebug, msg, keyvals)
+}
+
+// Info writes an info line with the given key value pairs.
+func Info(msg string, keyvals ...interface{}) {
+	write(LevelInfo, msg, keyvals)
+}
+
+// Warn writes a warning with the given key value pairs.
+func Warn(msg string, keyvals ...interface{}) {
+	write(LevelWarn, msg, keyvals)
+}
+
+// Error writes an error line with the given key value pairs.
+func Error(msg string, keyvals ...interface{}) {
+	write(LevelError, msg, keyvals)
+}
+
+// quote quotes a value if it is empty or can't be told apart from the
+// surrounding pairs otherwise.
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// write writes a line of the form "time level=info msg=... key=value" if a
+// file is open and the level isn't filtered out.
+func write(level Level, msg string, keyvals []interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if file == nil || level < minLevel {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s level=%s msg=%s", time.Now().Format(time.RFC3339), level, quote(msg))
+
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(missing)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		fmt.Fprintf(&sb, " %v=%s", keyvals[i], quote(fmt.Sprint(value)))
+	}
+
+	sb.WriteByte('\n')
+
+	_, _ = io.WriteString(file, sb.String())
+}
@@ -0,0 +1,139 @@
+// Package lscolors colors file names by their type the way ls does,
+// following the LS_COLORS environment variable.
+package lscolors
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// defaultColors are the colors used when LS_COLORS isn't set, which match
+// the defaults of dircolors for the most common types.
+const defaultColors = "di=01;34:ln=01;36:pi=33:so=01;35:bd=01;33:cd=01;33:ex=01;32:" +
+	"*.tar=01;31:*.tgz=01;31:*.gz=01;31:*.bz2=01;31:*.xz=01;31:*.zst=01;31:*.zip=01;31:*.7z=01;31:*.rar=01;31:" +
+	"*.jpg=01;35:*.jpeg=01;35:*.png=01;35:*.gif=01;35:*.bmp=01;35:*.webp=01;35:*.svg=01;35:*.tif=01;35:*.tiff=01;35:" +
+	"*.mp3=00;36:*.flac=00;36:*.ogg=00;36:*.wav=00;36:" +
+	"*.mp4=01;35:*.mkv=01;35:*.webm=01;35:*.mov=01;35:*.avi=01;35"
+
+// suffixColor represents the color of the files whose names end in a suffix.
+type suffixColor struct {
+	suffix string
+	code   string
+}
+
+// Palette represents the colors of each type of file, as SGR codes.
+type Palette struct {
+	types    map[string]string
+	suffixes []suffixColor
+}
+
+// Parse parses a palette in the format of LS_COLORS, a colon separated list
+// of type=code pairs, where the type is either a two letter code such as di
+// or ex or a pattern such as *.png matching the end of file names. Entries
+// which aren't colors, such as ln=target, are ignored.
+func Parse(value string) Palette {
+	palette := Palette{types: make(map[string]string)}
+
+	for _, entry := range strings.Split(value, ":") {
+		key, code, ok := strings.Cut(entry, "=")
+		if !ok || key == "" || !isCode(code) {
+			continue
+		}
+
+		if strings.HasPrefix(key, "*") {
+			palette.suffixes = append(palette.suffixes, suffixColor{suffix: key[1:], code: code})
+			continue
+		}
+
+		palette.types[key] = code
+	}
+
+	return palette
+}
+
+// Default returns the palette used when LS_COLORS isn't set.
+func Default() Palette {
+	return Parse(defaultColors)
+}
+
+// FromEnvironment returns the palette set by LS_COLORS, falling back to the
+// default palette if it is empty.
+func FromEnvironment() Palette {
+	if value := os.Getenv("LS_COLORS"); value != "" {
+		return Parse(value)
+	}
+
+	return Default()
+}
+
+// isCode returns true if a value is a valid SGR code.
+func isCode(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if (r < '0' || r > '9') && r != ';' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Code returns the SGR code a file with the given name and mode is colored
+// with, which is empty if it isn't colored.
+func (p Palette) Code(name string, mode fs.FileMode) string {
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		return p.types["ln"]
+	case mode.IsDir():
+		return p.types["di"]
+	case mode&fs.ModeNamedPipe != 0:
+		return p.types["pi"]
+	case mode&fs.ModeSocket != 0:
+		return p.types["so"]
+	case mode&fs.ModeCharDevice != 0:
+		return p.types["cd"]
+	case mode&fs.ModeDevice != 0:
+		return p.types["bd"]
+	case mode&0o111 != 0 && p.types["ex"] != "":
+		return p.types["ex"]
+	}
+
+	if code, ok := p.suffixCode(name); ok {
+		return code
+	}
+
+	return p.types["fi"]
+}
+
+// suffixCode returns the code of the pattern matching the end of a name,
+// preferring an exact match over one ignoring case.
+func (p Palette) suffixCode(name string) (string, bool) {
+	for _, suffix := range p.suffixes {
+		if strings.HasSuffix(name, suffix.suffix) {
+			return suffix.code, true
+		}
+	}
+
+	lowerName := strings.ToLower(name)
+	for _, suffix := range p.suffixes {
+		if strings.HasSuffix(lowerName, strings.ToLower(suffix.suffix)) {
+			return suffix.code, true
+		}
+	}
+
+	return "", false
+}
+
+// Paint colors a file name for a file with the given mode.
+func (p Palette) Paint(name string, mode fs.FileMode) string {
+	code := p.Code(name, mode)
+	if code == "" {
+		return name
+	}
+
+	return "\x1b[" + code + "m" + name + "\x1b[0m"
+}
@@ -0,0 +1,113 @@
+// Package modal implements a bubble which asks the user to confirm an action.
+package modal
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// maxWidth is the maximum width of the dialog, including its border.
+const maxWidth = 50
+
+// confirmHint is the hint shown below the message of a confirmation.
+const confirmHint = "enter confirm • esc cancel"
+
+// TitleColor represents the colors of the title.
+type TitleColor struct {
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+}
+
+// Bubble represents the properties of a modal bubble.
+type Bubble struct {
+	title       string
+	message     string
+	hint        string
+	titleColor  TitleColor
+	borderColor lipgloss.AdaptiveColor
+	visible     bool
+	width       int
+	height      int
+}
+
+// New creates a new instance of a modal.
+func New(titleColor TitleColor, borderColor lipgloss.AdaptiveColor) Bubble {
+	return Bubble{
+		titleColor:  titleColor,
+		borderColor: borderColor,
+	}
+}
+
+// Show shows the modal with the given title and message.
+func (b *Bubble) Show(title, message string) {
+	b.ShowWithHint(title, message, confirmHint)
+}
+
+// ShowWithHint shows the modal with the given title and message, and a hint
+// at the keys answering it.
+func (b *Bubble) ShowWithHint(title, message, hint string) {
+	b.title = title
+	b.message = message
+	b.hint = hint
+	b.visible = true
+}
+
+// Hide hides the modal.
+func (b *Bubble) Hide() {
+	b.visible = false
+}
+
+// Visible returns true if the modal is shown.
+func (b Bubble) Visible() bool {
+	return b.visible
+}
+
+// SetSize sets the size of the area the modal is centered in.
+func (b *Bubble) SetSize(w, h int) {
+	b.width = w
+	b.height = h
+}
+
+// SetBorderColor sets the current color of the border.
+func (b *Bubble) SetBorderColor(color lipgloss.AdaptiveColor) {
+	b.borderColor = color
+}
+
+// SetTitleColor sets the color of the title.
+func (b *Bubble) SetTitleColor(color TitleColor) {
+	b.titleColor = color
+}
+
+// View returns a string representation of the modal bubble centered in its area.
+func (b Bubble) View() string {
+	style := lipgloss.NewStyle().
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(b.borderColor)
+
+	width := maxWidth
+	if b.width-2 < width {
+		width = b.width - 2
+	}
+
+	contentWidth := width - style.GetHorizontalFrameSize()
+	if contentWidth < 1 || b.height < 1 {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Background(b.titleColor.Background).
+		Foreground(b.titleColor.Foreground).
+		Padding(0, 1).
+		Render(truncate.StringWithTail(b.title, uint(contentWidth-2), "..."))
+
+	message := lipgloss.NewStyle().Width(contentWidth).Render(b.message)
+	hint := lipgloss.NewStyle().Faint(true).Render(b.hint)
+
+	dialog := style.Width(width - style.GetHorizontalBorderSize()).Render(
+		lipgloss.JoinVertical(lipgloss.Left, title, "", message, "", hint),
+	)
+
+	return lipgloss.Place(b.width, b.height, lipgloss.Center, lipgloss.Center, dialog)
+}
@@ -0,0 +1,238 @@
+// Package picker implements a bubble which renders a list of items to choose from.
+package picker
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// Item represents a single entry in the picker.
+type Item struct {
+	Title string
+	Value string
+}
+
+// TitleColor represents the colors of the title.
+type TitleColor struct {
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+}
+
+// Bubble represents the properties of a picker bubble.
+type Bubble struct {
+	title             string
+	titleColor        TitleColor
+	items             []Item
+	cursor            int
+	offset            int
+	scrollOff         int
+	width             int
+	height            int
+	borderColor       lipgloss.AdaptiveColor
+	selectedItemColor lipgloss.AdaptiveColor
+	borderless        bool
+	active            bool
+	up                key.Binding
+	down              key.Binding
+}
+
+// New creates a new instance of a picker.
+func New(
+	active, borderless bool,
+	titleColor TitleColor,
+	borderColor, selectedItemColor lipgloss.AdaptiveColor,
+) Bubble {
+	return Bubble{
+		titleColor:        titleColor,
+		borderColor:       borderColor,
+		selectedItemColor: selectedItemColor,
+		borderless:        borderless,
+		active:            active,
+		up:                key.NewBinding(key.WithKeys("up", "k")),
+		down:              key.NewBinding(key.WithKeys("down", "j")),
+	}
+}
+
+// style returns the style of the picker based on the current border settings.
+func (b Bubble) style() lipgloss.Style {
+	border := lipgloss.NormalBorder()
+	if b.borderless {
+		border = lipgloss.HiddenBorder()
+	}
+
+	return lipgloss.NewStyle().
+		PaddingLeft(1).
+		PaddingRight(1).
+		Border(border).
+		BorderForeground(b.borderColor)
+}
+
+// listHeight returns the number of items which fit below the title.
+func (b Bubble) listHeight() int {
+	return b.height - b.style().GetVerticalFrameSize() - 2
+}
+
+// fixOffset scrolls the list so that the cursor is visible, keeping up to
+// scrollOff items visible above and below it.
+func (b *Bubble) fixOffset() {
+	height := b.listHeight()
+	if height < 1 {
+		return
+	}
+
+	margin := b.scrollOff
+	if margin > (height-1)/2 {
+		margin = (height - 1) / 2
+	}
+
+	if b.cursor-margin < b.offset {
+		b.offset = b.cursor - margin
+	}
+
+	if b.cursor+margin >= b.offset+height {
+		b.offset = b.cursor + margin - height + 1
+	}
+
+	if maxOffset := len(b.items) - height; b.offset > maxOffset {
+		b.offset = maxOffset
+	}
+
+	if b.offset < 0 {
+		b.offset = 0
+	}
+}
+
+// SetScrollOff sets the number of items kept visible above and below the
+// cursor when scrolling.
+func (b *Bubble) SetScrollOff(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+
+	b.scrollOff = lines
+	b.fixOffset()
+}
+
+// SetItems sets the title and the items to choose from, resetting the cursor.
+func (b *Bubble) SetItems(title string, items []Item) {
+	b.title = title
+	b.items = items
+	b.cursor = 0
+	b.offset = 0
+}
+
+// SelectedItem returns the item under the cursor.
+func (b Bubble) SelectedItem() (Item, bool) {
+	if len(b.items) == 0 {
+		return Item{}, false
+	}
+
+	return b.items[b.cursor], true
+}
+
+// CursorUp moves the cursor up one item.
+func (b *Bubble) CursorUp() {
+	if b.cursor > 0 {
+		b.cursor--
+	}
+
+	b.fixOffset()
+}
+
+// CursorDown moves the cursor down one item.
+func (b *Bubble) CursorDown() {
+	if b.cursor < len(b.items)-1 {
+		b.cursor++
+	}
+
+	b.fixOffset()
+}
+
+// SetSize sets the size of the bubble.
+func (b *Bubble) SetSize(w, h int) {
+	b.width = w
+	b.height = h
+	b.fixOffset()
+}
+
+// SetIsActive sets if the bubble is currently active.
+func (b *Bubble) SetIsActive(active bool) {
+	b.active = active
+}
+
+// SetBorderColor sets the current color of the border.
+func (b *Bubble) SetBorderColor(color lipgloss.AdaptiveColor) {
+	b.borderColor = color
+}
+
+// SetSelectedItemColor sets the color of the item under the cursor.
+func (b *Bubble) SetSelectedItemColor(color lipgloss.AdaptiveColor) {
+	b.selectedItemColor = color
+}
+
+// SetTitleColor sets the color of the title.
+func (b *Bubble) SetTitleColor(color TitleColor) {
+	b.titleColor = color
+}
+
+// SetBorderless sets weather or not to show the border.
+func (b *Bubble) SetBorderless(borderless bool) {
+	b.borderless = borderless
+}
+
+// Update handles moving the cursor of a picker bubble.
+func (b Bubble) Update(msg tea.Msg) (Bubble, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && b.active {
+		switch {
+		case key.Matches(msg, b.up):
+			b.CursorUp()
+		case key.Matches(msg, b.down):
+			b.CursorDown()
+		}
+	}
+
+	return b, nil
+}
+
+// View returns a string representation of the picker bubble.
+func (b Bubble) View() string {
+	style := b.style()
+	width := b.width - style.GetHorizontalFrameSize()
+	height := b.height - style.GetVerticalFrameSize()
+
+	if width < 1 || height < 1 {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Background(b.titleColor.Background).
+		Foreground(b.titleColor.Foreground).
+		Padding(0, 1).
+		Render(truncate.StringWithTail(b.title, uint(width-2), "..."))
+
+	lines := []string{title, ""}
+
+	end := b.offset + b.listHeight()
+	if end > len(b.items) {
+		end = len(b.items)
+	}
+
+	for i := b.offset; i < end; i++ {
+		line := truncate.StringWithTail(b.items[i].Title, uint(width), "...")
+		if i == b.cursor {
+			line = lipgloss.NewStyle().Foreground(b.selectedItemColor).Bold(true).Render(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return style.
+		Width(b.width - style.GetHorizontalBorderSize()).
+		Height(b.height - style.GetVerticalBorderSize()).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,86 @@
+// Package previewer implements previewing files with external commands
+// configured per file extension.
+package previewer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// timeout is how long a preview command may run.
+	timeout = 5 * time.Second
+
+	// maxOutput is the number of bytes of output kept from a preview command.
+	maxOutput = 1024 * 1024
+)
+
+// ErrEmptyCommand is returned when the command configured for an extension is empty.
+var ErrEmptyCommand = errors.New("empty preview command")
+
+// Command returns the command configured for a file extension, which may be
+// configured with or without its leading dot.
+func Command(previewers map[string]string, ext string) (string, bool) {
+	if ext == "" {
+		return "", false
+	}
+
+	if command, ok := previewers[ext]; ok {
+		return command, true
+	}
+
+	command, ok := previewers[strings.TrimPrefix(ext, ".")]
+
+	return command, ok
+}
+
+// Run runs a preview command with the path of a file as its last argument
+// and returns what it writes to stdout. The command is killed once it runs
+// longer than the timeout, and output beyond maxOutput bytes is cut off.
+func Run(command, path string) (string, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", ErrEmptyCommand
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], path)...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	output, err := io.ReadAll(io.LimitReader(stdout, maxOutput+1))
+	if err != nil || len(output) > maxOutput {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s\n…(truncated)", output[:maxOutput]), nil
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%s took longer than %s", fields[0], timeout)
+		}
+
+		return "", err
+	}
+
+	return string(output), nil
+}
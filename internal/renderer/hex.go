@@ -0,0 +1,68 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// hexOffsetWidth is the width of the offset column of a hex dump, including its separator.
+	hexOffsetWidth = 10
+
+	// hexGroupSize is the number of bytes per line of a hex dump is a multiple of.
+	hexGroupSize = 4
+
+	// maxHexBytesPerLine is the maximum number of bytes shown on a line of a hex dump.
+	maxHexBytesPerLine = 32
+)
+
+// hexBytesPerLine returns the number of bytes fitting on a line of the given
+// width, each byte taking three cells in the hex column and one in the ASCII
+// column, rounded down to a multiple of hexGroupSize.
+func hexBytesPerLine(width int) int {
+	n := (width - hexOffsetWidth - 1) / 4
+	n -= n % hexGroupSize
+	if n < hexGroupSize {
+		return hexGroupSize
+	}
+
+	if n > maxHexBytesPerLine {
+		return maxHexBytesPerLine
+	}
+
+	return n
+}
+
+// HexDump formats data like xxd, showing the offset, the bytes in hex and
+// their printable ASCII characters on each line, fitting as many bytes on a
+// line as the given width allows.
+func HexDump(data []byte, width int) string {
+	perLine := hexBytesPerLine(width)
+
+	var lines []string
+	for offset := 0; offset < len(data); offset += perLine {
+		end := offset + perLine
+		if end > len(data) {
+			end = len(data)
+		}
+
+		var hex, ascii strings.Builder
+		for i := offset; i < offset+perLine; i++ {
+			if i < end {
+				fmt.Fprintf(&hex, "%02x ", data[i])
+			} else {
+				hex.WriteString("   ")
+			}
+
+			if i < end && data[i] >= 0x20 && data[i] < 0x7f {
+				ascii.WriteByte(data[i])
+			} else if i < end {
+				ascii.WriteByte('.')
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%08x: %s %s", offset, hex.String(), ascii.String()))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// halfBlock is the character used to render two vertical pixels in a single cell,
+// the foreground color is used for the top pixel and the background for the bottom.
+const halfBlock = "▀"
+
+// scaleImage resizes an image to fit within the given number of cells using
+// nearest neighbour sampling, each cell holding two pixels vertically.
+func scaleImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth == 0 || srcHeight == 0 || width <= 0 || height <= 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	dstWidth := width
+	dstHeight := srcHeight * dstWidth / srcWidth
+	if dstHeight > height*2 {
+		dstHeight = height * 2
+		dstWidth = srcWidth * dstHeight / srcHeight
+	}
+
+	return resample(img, dstWidth, dstHeight)
+}
+
+// Downscale resizes an image using nearest neighbour sampling so that
+// neither of its sides is longer than maxSide pixels, keeping its aspect
+// ratio. Images which already fit are returned as they are.
+func Downscale(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= maxSide && srcHeight <= maxSide {
+		return img
+	}
+
+	if srcWidth >= srcHeight {
+		return resample(img, maxSide, srcHeight*maxSide/srcWidth)
+	}
+
+	return resample(img, srcWidth*maxSide/srcHeight, maxSide)
+}
+
+// resample copies an image into one of the given dimensions using nearest
+// neighbour sampling.
+func resample(img image.Image, dstWidth, dstHeight int) image.Image {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	if dstWidth < 1 {
+		dstWidth = 1
+	}
+
+	if dstHeight < 1 {
+		dstHeight = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			dst.Set(x, y, img.At(
+				bounds.Min.X+x*srcWidth/dstWidth,
+				bounds.Min.Y+y*srcHeight/dstHeight,
+			))
+		}
+	}
+
+	return dst
+}
+
+// rgb returns the 8 bit color channels of the pixel at the given position.
+func rgb(img image.Image, x, y int) (uint32, uint32, uint32) {
+	r, g, b, _ := img.At(x, y).RGBA()
+
+	return r >> 8, g >> 8, b >> 8
+}
+
+// RenderImage converts an image into true color half block characters fitting
+// within the given number of cells.
+func RenderImage(img image.Image, width, height int) string {
+	scaled := scaleImage(img, width, height)
+	bounds := scaled.Bounds()
+
+	var sb strings.Builder
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			tr, tg, tb := rgb(scaled, x, y)
+			fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm", tr, tg, tb)
+
+			if y+1 < bounds.Max.Y {
+				br, bg, bb := rgb(scaled, x, y+1)
+				fmt.Fprintf(&sb, "\x1b[48;2;%d;%d;%dm", br, bg, bb)
+			}
+
+			sb.WriteString(halfBlock)
+		}
+
+		sb.WriteString("\x1b[0m\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,316 @@
+// Package renderer implements a bubble which renders plain text content.
+package renderer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// Bubble represents the properties of a renderer bubble.
+type Bubble struct {
+	viewport        viewport.Model
+	spinner         spinner.Model
+	borderColor     lipgloss.AdaptiveColor
+	lineNumberColor lipgloss.AdaptiveColor
+	borderless      bool
+	active          bool
+	wrap            bool
+	lineNumbers     bool
+	loading         bool
+	content         string
+	lineRows        []int
+}
+
+// New creates a new instance of a renderer.
+func New(active, borderless bool, borderColor lipgloss.AdaptiveColor) Bubble {
+	b := Bubble{
+		viewport:    viewport.New(0, 0),
+		spinner:     spinner.New(),
+		borderColor: borderColor,
+		borderless:  borderless,
+		active:      active,
+	}
+	b.spinner.Spinner = spinner.Dot
+	b.viewport.Style = b.style()
+
+	return b
+}
+
+// style returns the style of the viewport based on the current border settings.
+func (b Bubble) style() lipgloss.Style {
+	border := lipgloss.NormalBorder()
+	if b.borderless {
+		border = lipgloss.HiddenBorder()
+	}
+
+	return lipgloss.NewStyle().
+		PaddingLeft(1).
+		PaddingRight(1).
+		Border(border).
+		BorderForeground(b.borderColor)
+}
+
+// tabWidth is the number of columns between tab stops.
+const tabWidth = 4
+
+// expandTabs replaces the tabs of a line with spaces up to the next tab
+// stop, so that the width of the line can be measured.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	var sb strings.Builder
+	column := 0
+	for i, part := range strings.Split(line, "\t") {
+		if i > 0 {
+			spaces := tabWidth - column%tabWidth
+			sb.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+		}
+
+		sb.WriteString(part)
+		column += ansi.PrintableRuneWidth(part)
+	}
+
+	return sb.String()
+}
+
+// wrapLine wraps a line at word boundaries to the given width, breaking
+// words longer than the width. The lines it is wrapped onto are indented
+// like the line itself.
+func wrapLine(line string, width int) string {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+	if ansi.PrintableRuneWidth(indent) > width/2 {
+		indent = ""
+	}
+
+	textWidth := width - ansi.PrintableRuneWidth(indent)
+	wrapped := wrap.String(wordwrap.String(trimmed, textWidth), textWidth)
+
+	lines := strings.Split(wrapped, "\n")
+	for i := range lines {
+		lines[i] = indent + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// fitLine wraps or truncates a line if it is wider than the given width,
+// expanding its tabs first.
+func fitLine(line string, width int, wrapLines bool) string {
+	line = expandTabs(line)
+
+	switch {
+	case ansi.PrintableRuneWidth(line) <= width:
+		return line
+	case wrapLines:
+		return wrapLine(line, width)
+	default:
+		return truncate.String(line, uint(width))
+	}
+}
+
+// layout fits the lines of the content to the given width, prefixing each
+// line with its line number in a right aligned gutter if enabled. Lines
+// wrapped onto several rows are only numbered once. The row each line
+// starts at is returned as well.
+func (b Bubble) layout(width int) (string, []int) {
+	content := b.content
+	if b.lineNumbers {
+		content = strings.TrimSuffix(content, "\n")
+	}
+
+	lines := strings.Split(content, "\n")
+
+	gutterWidth := 0
+	if b.lineNumbers && width > 1 {
+		gutterWidth = len(strconv.Itoa(len(lines))) + 1
+		if gutterWidth >= width {
+			gutterWidth = 0
+		}
+	}
+
+	gutterStyle := lipgloss.NewStyle().Faint(true).Foreground(b.lineNumberColor)
+
+	lineRows := make([]int, len(lines))
+	row := 0
+	for i, line := range lines {
+		line = fitLine(line, width-gutterWidth, b.wrap)
+		lineRows[i] = row
+		row += strings.Count(line, "\n") + 1
+
+		if gutterWidth == 0 {
+			lines[i] = line
+			continue
+		}
+
+		rows := strings.Split(line, "\n")
+		for j := range rows {
+			number := ""
+			if j == 0 {
+				number = strconv.Itoa(i + 1)
+			}
+
+			rows[j] = gutterStyle.Render(fmt.Sprintf("%*s ", gutterWidth-1, number)) + rows[j]
+		}
+
+		lines[i] = strings.Join(rows, "\n")
+	}
+
+	return strings.Join(lines, "\n"), lineRows
+}
+
+// render sets the content of the viewport sized to the current dimensions,
+// wrapping or truncating lines which don't fit.
+func (b *Bubble) render() {
+	width, height := b.Size()
+
+	content := b.content
+	b.lineRows = nil
+	if width > 0 {
+		content, b.lineRows = b.layout(width)
+	}
+
+	b.viewport.SetContent(
+		lipgloss.NewStyle().
+			Width(width).
+			Height(height).
+			Render(content),
+	)
+}
+
+// SetContent sets the content to render, replacing the loading spinner.
+func (b *Bubble) SetContent(content string) {
+	b.loading = false
+	b.content = content
+	b.render()
+}
+
+// StartLoading shows a spinner in place of the content until new content is set.
+func (b *Bubble) StartLoading() tea.Cmd {
+	if b.loading {
+		return nil
+	}
+
+	b.loading = true
+
+	return b.spinner.Tick
+}
+
+// SetSize sets the size of the bubble.
+func (b *Bubble) SetSize(w, h int) {
+	b.viewport.Width = w
+	b.viewport.Height = h
+	b.render()
+}
+
+// Size returns the width and height available for content.
+func (b Bubble) Size() (int, int) {
+	return b.viewport.Width - b.viewport.Style.GetHorizontalFrameSize(),
+		b.viewport.Height - b.viewport.Style.GetVerticalFrameSize()
+}
+
+// SetWrap sets weather or not lines wider than the bubble are wrapped
+// instead of truncated.
+func (b *Bubble) SetWrap(wrap bool) {
+	b.wrap = wrap
+	b.render()
+}
+
+// SetLineNumbers sets weather or not lines are prefixed with their line number.
+func (b *Bubble) SetLineNumbers(lineNumbers bool) {
+	b.lineNumbers = lineNumbers
+	b.render()
+}
+
+// SetLineNumberColor sets the color of the line numbers.
+func (b *Bubble) SetLineNumberColor(color lipgloss.AdaptiveColor) {
+	b.lineNumberColor = color
+	b.render()
+}
+
+// SetIsActive sets if the bubble is currently active.
+func (b *Bubble) SetIsActive(active bool) {
+	b.active = active
+}
+
+// SetBorderColor sets the current color of the border.
+func (b *Bubble) SetBorderColor(color lipgloss.AdaptiveColor) {
+	b.borderColor = color
+}
+
+// SetBorderless sets weather or not to show the border.
+func (b *Bubble) SetBorderless(borderless bool) {
+	b.borderless = borderless
+}
+
+// ScrollToLine scrolls the line of the content with the given number,
+// counting from 1, to the top of the viewport. Numbers past the last line
+// scroll to the last line.
+func (b *Bubble) ScrollToLine(line int) {
+	if len(b.lineRows) == 0 {
+		return
+	}
+
+	if line < 1 {
+		line = 1
+	}
+
+	if line > len(b.lineRows) {
+		line = len(b.lineRows)
+	}
+
+	b.viewport.GotoTop()
+	b.viewport.LineDown(b.lineRows[line-1])
+}
+
+// GotoTop jumps to the top of the viewport.
+func (b *Bubble) GotoTop() {
+	b.viewport.GotoTop()
+}
+
+// ScrollPercent returns how far the viewport is scrolled, from 0 to 1.
+func (b Bubble) ScrollPercent() float64 {
+	return b.viewport.ScrollPercent()
+}
+
+// Update handles updating the UI of a renderer bubble.
+func (b Bubble) Update(msg tea.Msg) (Bubble, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if _, ok := msg.(spinner.TickMsg); ok {
+		if b.loading {
+			b.spinner, cmd = b.spinner.Update(msg)
+		}
+
+		return b, cmd
+	}
+
+	if b.active {
+		b.viewport, cmd = b.viewport.Update(msg)
+	}
+
+	return b, cmd
+}
+
+// View returns a string representation of the renderer bubble.
+func (b Bubble) View() string {
+	b.viewport.Style = b.style()
+	if b.loading {
+		b.viewport.SetContent(fmt.Sprintf("%s Loading...", b.spinner.View()))
+	}
+
+	return b.viewport.View()
+}
@@ -0,0 +1,73 @@
+package renderer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/muesli/reflow/ansi"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "no tabs", line: "a b", want: "a b"},
+		{name: "leading tabs", line: "\t\tx", want: "        x"},
+		{name: "tab after text", line: "ab\tc", want: "ab  c"},
+		{name: "tab at a tab stop", line: "abcd\te", want: "abcd    e"},
+		{name: "escape codes", line: "\x1b[31mab\x1b[0m\tc", want: "\x1b[31mab\x1b[0m  c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandTabs(tt.line); got != tt.want {
+				t.Errorf("expandTabs(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutTabIndentedLines(t *testing.T) {
+	const width = 26
+
+	content := "func f() {\n\t\tif x { return averyveryverylongidentifier }\n}"
+
+	tests := []struct {
+		name         string
+		wrap         bool
+		wantRows     int
+		wantLineRows []int
+	}{
+		{name: "truncated", wrap: false, wantRows: 3, wantLineRows: []int{0, 1, 2}},
+		{name: "wrapped", wrap: true, wantRows: 6, wantLineRows: []int{0, 1, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bubble{content: content, wrap: tt.wrap, lineNumbers: true}
+
+			got, lineRows := b.layout(width)
+			rows := strings.Split(got, "\n")
+			if len(rows) != tt.wantRows {
+				t.Fatalf("layout() has %d rows, want %d:\n%s", len(rows), tt.wantRows, got)
+			}
+
+			for _, row := range rows {
+				if strings.Contains(row, "\t") {
+					t.Errorf("layout() row %q contains a tab", row)
+				}
+
+				if w := ansi.PrintableRuneWidth(row); w > width {
+					t.Errorf("layout() row %q is %d wide, want at most %d", row, w, width)
+				}
+			}
+
+			if !reflect.DeepEqual(lineRows, tt.wantLineRows) {
+				t.Errorf("layout() line rows = %v, want %v", lineRows, tt.wantLineRows)
+			}
+		})
+	}
+}
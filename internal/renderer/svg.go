@@ -0,0 +1,48 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// RasterizeSVG draws an SVG image as large as fits within the given number
+// of pixels, keeping its aspect ratio. Transparent areas are drawn white so
+// that dark strokes stay visible on a dark terminal.
+func RasterizeSVG(r io.Reader, maxWidth, maxHeight int) (img image.Image, err error) {
+	// The SVG parser panics on some malformed paths.
+	defer func() {
+		if p := recover(); p != nil {
+			img, err = nil, fmt.Errorf("unable to draw the SVG: %v", p)
+		}
+	}()
+
+	icon, err := oksvg.ReadIconStream(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+		return nil, errors.New("the SVG has no size")
+	}
+
+	scale := math.Min(float64(maxWidth)/icon.ViewBox.W, float64(maxHeight)/icon.ViewBox.H)
+	width := int(math.Max(1, icon.ViewBox.W*scale))
+	height := int(math.Max(1, icon.ViewBox.H*scale))
+
+	icon.SetTarget(0, 0, float64(width), float64(height))
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+
+	scanner := rasterx.NewScannerGV(width, height, dst, dst.Bounds())
+	icon.Draw(rasterx.NewDasher(width, height, scanner), 1)
+
+	return dst, nil
+}
@@ -0,0 +1,70 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// columnSeparator is placed between the columns of a table.
+const columnSeparator = " │ "
+
+// RenderTable renders rows as a table with aligned columns, the first row
+// being the header. Line breaks within fields are replaced with spaces and
+// lines wider than the given width are cut off with an ellipsis.
+func RenderTable(rows [][]string, width int, headerColor, separatorColor lipgloss.AdaptiveColor) string {
+	var widths []int
+	for _, row := range rows {
+		for i, field := range row {
+			row[i] = strings.ReplaceAll(field, "\n", " ")
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if w := lipgloss.Width(row[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(headerColor)
+	separatorStyle := lipgloss.NewStyle().Foreground(separatorColor)
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		fields := make([]string, len(widths))
+		for j := range widths {
+			field := ""
+			if j < len(row) {
+				field = row[j]
+			}
+
+			field += strings.Repeat(" ", widths[j]-lipgloss.Width(field))
+			if i == 0 {
+				field = headerStyle.Render(field)
+			}
+
+			fields[j] = field
+		}
+
+		lines = append(lines, strings.Join(fields, separatorStyle.Render(columnSeparator)))
+
+		if i == 0 {
+			rules := make([]string, len(widths))
+			for j, w := range widths {
+				rules[j] = strings.Repeat("─", w)
+			}
+
+			lines = append(lines, separatorStyle.Render(strings.Join(rules, "─┼─")))
+		}
+	}
+
+	if width > 0 {
+		for i, line := range lines {
+			lines[i] = truncate.StringWithTail(line, uint(width), "…")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+// Package sortorder compares names in natural order, the way people expect
+// a listing of files to be sorted.
+package sortorder
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Less returns true if a sorts before b in natural order. Runs of the digits
+// 0 to 9 are compared by their numeric value, so "file2" sorts before
+// "file10", and letters are compared ignoring case unless caseSensitive is
+// set. Names which only differ in case or leading zeros are ordered by
+// comparing them as they are, so that the order stays total.
+func Less(a, b string, caseSensitive bool) bool {
+	if c := compare(a, b, caseSensitive); c != 0 {
+		return c < 0
+	}
+
+	return a < b
+}
+
+// compare returns -1, 0 or 1 as a sorts before, with or after b in natural order.
+func compare(a, b string, caseSensitive bool) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var numA, numB string
+			numA, a = digitRun(a)
+			numB, b = digitRun(b)
+
+			if c := compareNumbers(numA, numB); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		runeA, sizeA := utf8.DecodeRuneInString(a)
+		runeB, sizeB := utf8.DecodeRuneInString(b)
+		a, b = a[sizeA:], b[sizeB:]
+
+		if !caseSensitive {
+			runeA, runeB = unicode.ToLower(runeA), unicode.ToLower(runeB)
+		}
+
+		switch {
+		case runeA < runeB:
+			return -1
+		case runeA > runeB:
+			return 1
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareNumbers compares two runs of digits by their numeric value.
+func compareNumbers(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// digitRun splits s after its leading run of digits.
+func digitRun(s string) (string, string) {
+	end := 0
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+
+	return s[:end], s[end:]
+}
+
+// isDigit returns true if c is one of the digits 0 to 9.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
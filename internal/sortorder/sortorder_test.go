@@ -0,0 +1,86 @@
+package sortorder
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLess(t *testing.T) {
+	tests := []struct {
+		name          string
+		names         []string
+		caseSensitive bool
+		want          []string
+	}{
+		{
+			name:  "numbers by value",
+			names: []string{"file10", "file2", "file1"},
+			want:  []string{"file1", "file2", "file10"},
+		},
+		{
+			name:  "several numbers",
+			names: []string{"v1.10.0", "v1.9.2", "v1.9.10", "v1.2"},
+			want:  []string{"v1.2", "v1.9.2", "v1.9.10", "v1.10.0"},
+		},
+		{
+			name:  "leading zeros",
+			names: []string{"img010", "img9", "img0010", "img08"},
+			want:  []string{"img08", "img9", "img0010", "img010"},
+		},
+		{
+			name:  "numbers longer than an int64",
+			names: []string{"n100000000000000000000", "n99999999999999999999"},
+			want:  []string{"n99999999999999999999", "n100000000000000000000"},
+		},
+		{
+			name:  "digits before letters",
+			names: []string{"a", "1", "B", "10"},
+			want:  []string{"1", "10", "a", "B"},
+		},
+		{
+			name:  "prefix first",
+			names: []string{"notes.txt", "notes", "notes2"},
+			want:  []string{"notes", "notes.txt", "notes2"},
+		},
+		{
+			name:  "ignoring case",
+			names: []string{"beta", "Alpha", "alpha", "Gamma"},
+			want:  []string{"Alpha", "alpha", "beta", "Gamma"},
+		},
+		{
+			name:          "case sensitive",
+			names:         []string{"beta", "Alpha", "alpha", "Gamma"},
+			caseSensitive: true,
+			want:          []string{"Alpha", "Gamma", "alpha", "beta"},
+		},
+		{
+			name:  "unicode ignoring case",
+			names: []string{"Über2", "über10", "über1", "Zebra", "Äpfel"},
+			want:  []string{"Zebra", "Äpfel", "über1", "Über2", "über10"},
+		},
+		{
+			name:  "unicode letters and digits",
+			names: []string{"фото12", "Фото3", "写真2", "写真10"},
+			want:  []string{"Фото3", "фото12", "写真2", "写真10"},
+		},
+		{
+			name:  "non ascii digits aren't numbers",
+			names: []string{"a٢", "a١٠", "a1"},
+			want:  []string{"a1", "a١٠", "a٢"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := append([]string(nil), tt.names...)
+			sort.Slice(got, func(i, j int) bool {
+				return Less(got[i], got[j], tt.caseSensitive)
+			})
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted %q to %q, want %q", tt.names, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package strfmt
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/knipferrc/teacup/filetree"
+	"github.com/mattn/go-runewidth"
+)
+
+// SizeFormat represents how byte counts are formatted.
+type SizeFormat int
+
+const (
+	// HumanReadable formats byte counts with a unit, such as 1.2K, the way
+	// the filetree does.
+	HumanReadable SizeFormat = iota
+
+	// ExactBytes formats byte counts as the exact number of bytes.
+	ExactBytes
+)
+
+// TruncateMiddle shortens a string to the given width by replacing its
+// middle with tail, keeping the start and the end, such as an extension, visible.
+func TruncateMiddle(s string, width int, tail string) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+
+	keep := width - runewidth.StringWidth(tail)
+	if keep <= 0 {
+		return runewidth.Truncate(s, width, "")
+	}
+
+	runes := []rune(s)
+	endWidth := keep / 2
+	start := runewidth.Truncate(s, keep-endWidth, "")
+
+	end := len(runes)
+	for w := 0; end > 0; end-- {
+		w += runewidth.RuneWidth(runes[end-1])
+		if w > endWidth {
+			break
+		}
+	}
+
+	return start + tail + string(runes[end:])
+}
+
+// FormatSize converts a byte count to a string in the given format, exact
+// byte counts being grouped by thousands.
+func FormatSize(size int64, format SizeFormat) string {
+	if format == HumanReadable {
+		return filetree.ConvertBytesToSizeString(size)
+	}
+
+	digits := strconv.FormatInt(size, 10)
+	sign := ""
+	if size < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	grouped := make([]byte, 0, len(digits)+len(digits)/3)
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped = append(grouped, ',')
+		}
+
+		grouped = append(grouped, digits[i])
+	}
+
+	return fmt.Sprintf("%s%sB", sign, grouped)
+}
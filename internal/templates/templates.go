@@ -0,0 +1,67 @@
+// Package templates implements listing the files new files can be created from.
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/config"
+)
+
+// DirName is the name of the directory next to the config file templates are stored in.
+const DirName = "templates"
+
+// Template represents a file new files can be created from.
+type Template struct {
+	Name string
+	Path string
+}
+
+// Dir returns the directory templates are stored in.
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, DirName), nil
+}
+
+// List returns the templates sorted by name, leaving out hidden files and
+// directories. There are none if the templates directory doesn't exist.
+func List() ([]Template, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Template
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		// Follow symlinks, so templates can be kept elsewhere.
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		list = append(list, Template{Name: entry.Name(), Path: path})
+	}
+
+	return list, nil
+}
@@ -16,8 +16,12 @@ type Theme struct {
 	StatusBarTotalFilesBackgroundColor   lipgloss.AdaptiveColor
 	StatusBarLogoForegroundColor         lipgloss.AdaptiveColor
 	StatusBarLogoBackgroundColor         lipgloss.AdaptiveColor
+	StatusBarSegmentForegroundColor      lipgloss.AdaptiveColor
+	StatusBarSegmentBackgroundColor      lipgloss.AdaptiveColor
 	TitleBackgroundColor                 lipgloss.AdaptiveColor
 	TitleForegroundColor                 lipgloss.AdaptiveColor
+	LineNumberColor                      lipgloss.AdaptiveColor
+	WarningColor                         lipgloss.AdaptiveColor
 }
 
 // themeMap represents the mapping of different themes.
@@ -35,8 +39,12 @@ var themeMap = map[string]Theme{
 		StatusBarTotalFilesBackgroundColor:   lipgloss.AdaptiveColor{Dark: "#A550DF", Light: "#A550DF"},
 		StatusBarLogoForegroundColor:         lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
 		StatusBarLogoBackgroundColor:         lipgloss.AdaptiveColor{Dark: "#6124DF", Light: "#6124DF"},
+		StatusBarSegmentForegroundColor:      lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarSegmentBackgroundColor:      lipgloss.AdaptiveColor{Dark: "#874BFD", Light: "#874BFD"},
 		TitleBackgroundColor:                 lipgloss.AdaptiveColor{Dark: "63", Light: "63"},
 		TitleForegroundColor:                 lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		LineNumberColor:                      lipgloss.AdaptiveColor{Dark: "#6c6c6c", Light: "#a8a8a8"},
+		WarningColor:                         lipgloss.AdaptiveColor{Dark: "#ff5f87", Light: "#ff5f87"},
 	},
 	"gruvbox": {
 		SelectedTreeItemColor:                lipgloss.AdaptiveColor{Dark: "#d65d0e", Light: "#d65d0e"},
@@ -51,8 +59,12 @@ var themeMap = map[string]Theme{
 		StatusBarTotalFilesBackgroundColor:   lipgloss.AdaptiveColor{Dark: "#ebcb8b", Light: "#ebcb8b"},
 		StatusBarLogoForegroundColor:         lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
 		StatusBarLogoBackgroundColor:         lipgloss.AdaptiveColor{Dark: "#458588", Light: "#458588"},
+		StatusBarSegmentForegroundColor:      lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarSegmentBackgroundColor:      lipgloss.AdaptiveColor{Dark: "#689d6a", Light: "#689d6a"},
 		TitleBackgroundColor:                 lipgloss.AdaptiveColor{Dark: "#d65d0e", Light: "#d65d0e"},
 		TitleForegroundColor:                 lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		LineNumberColor:                      lipgloss.AdaptiveColor{Dark: "#928374", Light: "#928374"},
+		WarningColor:                         lipgloss.AdaptiveColor{Dark: "#fb4934", Light: "#fb4934"},
 	},
 	"nord": {
 		SelectedTreeItemColor:                lipgloss.AdaptiveColor{Dark: "#d08770", Light: "#d08770"},
@@ -67,9 +79,41 @@ var themeMap = map[string]Theme{
 		StatusBarTotalFilesBackgroundColor:   lipgloss.AdaptiveColor{Dark: "#ebcb8b", Light: "#ebcb8b"},
 		StatusBarLogoForegroundColor:         lipgloss.AdaptiveColor{Dark: "#e5e9f0", Light: "#e5e9f0"},
 		StatusBarLogoBackgroundColor:         lipgloss.AdaptiveColor{Dark: "#81a1c1", Light: "#81a1c1"},
+		StatusBarSegmentForegroundColor:      lipgloss.AdaptiveColor{Dark: "#e5e9f0", Light: "#e5e9f0"},
+		StatusBarSegmentBackgroundColor:      lipgloss.AdaptiveColor{Dark: "#5e81ac", Light: "#5e81ac"},
 		TitleBackgroundColor:                 lipgloss.AdaptiveColor{Dark: "#d08770", Light: "#d08770"},
 		TitleForegroundColor:                 lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		LineNumberColor:                      lipgloss.AdaptiveColor{Dark: "#616e88", Light: "#7b88a1"},
+		WarningColor:                         lipgloss.AdaptiveColor{Dark: "#bf616a", Light: "#bf616a"},
 	},
+	"light": {
+		SelectedTreeItemColor:                lipgloss.AdaptiveColor{Dark: "#0550ae", Light: "#0550ae"},
+		UnselectedTreeItemColor:              lipgloss.AdaptiveColor{Dark: "#24292f", Light: "#24292f"},
+		ActiveBoxBorderColor:                 lipgloss.AdaptiveColor{Dark: "#8250df", Light: "#8250df"},
+		InactiveBoxBorderColor:               lipgloss.AdaptiveColor{Dark: "#8c959f", Light: "#8c959f"},
+		StatusBarSelectedFileForegroundColor: lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarSelectedFileBackgroundColor: lipgloss.AdaptiveColor{Dark: "#8250df", Light: "#8250df"},
+		StatusBarBarForegroundColor:          lipgloss.AdaptiveColor{Dark: "#24292f", Light: "#24292f"},
+		StatusBarBarBackgroundColor:          lipgloss.AdaptiveColor{Dark: "#eaeef2", Light: "#eaeef2"},
+		StatusBarTotalFilesForegroundColor:   lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarTotalFilesBackgroundColor:   lipgloss.AdaptiveColor{Dark: "#1a7f37", Light: "#1a7f37"},
+		StatusBarLogoForegroundColor:         lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarLogoBackgroundColor:         lipgloss.AdaptiveColor{Dark: "#0550ae", Light: "#0550ae"},
+		StatusBarSegmentForegroundColor:      lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		StatusBarSegmentBackgroundColor:      lipgloss.AdaptiveColor{Dark: "#953800", Light: "#953800"},
+		TitleBackgroundColor:                 lipgloss.AdaptiveColor{Dark: "#0550ae", Light: "#0550ae"},
+		TitleForegroundColor:                 lipgloss.AdaptiveColor{Dark: "#ffffff", Light: "#ffffff"},
+		LineNumberColor:                      lipgloss.AdaptiveColor{Dark: "#8c959f", Light: "#8c959f"},
+		WarningColor:                         lipgloss.AdaptiveColor{Dark: "#cf222e", Light: "#cf222e"},
+	},
+}
+
+// names lists the bundled themes in the order they are cycled through.
+var names = []string{"default", "gruvbox", "nord", "light"}
+
+// Names returns the names of the bundled themes.
+func Names() []string {
+	return append([]string(nil), names...)
 }
 
 // GetTheme returns a theme based on the given name.
@@ -81,6 +125,8 @@ func GetTheme(theme string) Theme {
 		return themeMap["gruvbox"]
 	case "nord":
 		return themeMap["nord"]
+	case "light":
+		return themeMap["light"]
 	default:
 		return themeMap["default"]
 	}
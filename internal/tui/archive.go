@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/picker"
+	"github.com/knipferrc/fm/internal/renderer"
+	"github.com/knipferrc/fm/internal/strfmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archiveBrowser represents the archive browsed in the picker and the slash
+// separated directory within it which is listed, empty for its root.
+type archiveBrowser struct {
+	path string
+	dir  string
+}
+
+type archiveDirMsg struct {
+	path  string
+	dir   string
+	items []filesystem.ArchiveItem
+	err   error
+}
+
+// entryPath returns the path a file within the archive is previewed as.
+func (a archiveBrowser) entryPath(name string) string {
+	return filepath.Join(a.path, filepath.FromSlash(name))
+}
+
+// parentDir returns the directory containing the listed one.
+func (a archiveBrowser) parentDir() string {
+	if i := strings.LastIndexByte(a.dir, '/'); i >= 0 {
+		return a.dir[:i]
+	}
+
+	return ""
+}
+
+// listArchiveDirCmd lists a directory within an archive, sorting names
+// ignoring case unless caseSensitive is set.
+func listArchiveDirCmd(path, dir string, caseSensitive bool) tea.Cmd {
+	return func() tea.Msg {
+		items, err := filesystem.ListArchiveDir(path, dir, caseSensitive)
+
+		return archiveDirMsg{path: path, dir: dir, items: items, err: err}
+	}
+}
+
+// readArchiveEntryCmd reads up to maxBytes bytes of a file within an archive
+// and shows it as text, highlighting its syntax unless syntaxTheme is empty,
+// or as a hex dump fitting the given width if it is binary.
+func readArchiveEntryCmd(archive archiveBrowser, name string, maxBytes int64, syntaxTheme string, width int) tea.Cmd {
+	return func() tea.Msg {
+		path := archive.entryPath(name)
+
+		data, truncated, err := filesystem.ReadArchiveFile(archive.path, name, maxBytes)
+		if err != nil {
+			return archiveContentMsg{path: path, content: err.Error()}
+		}
+
+		encoding, ok := filesystem.DetectTextEncoding(data, truncated)
+		if !ok {
+			if len(data) > hexPreviewBytes {
+				data, truncated = data[:hexPreviewBytes], true
+			}
+
+			return archiveContentMsg{path: path, content: withTruncatedMarker(renderer.HexDump(data, width), truncated)}
+		}
+
+		content := filesystem.DecodeText(data, encoding)
+		if syntaxTheme != "" {
+			content = highlight(name, content, syntaxTheme)
+		}
+
+		return archiveContentMsg{path: path, content: withTruncatedMarker(content, truncated)}
+	}
+}
+
+// withGlyph puts a glyph in front of a title unless it is empty.
+func withGlyph(glyph, title string) string {
+	if glyph == "" {
+		return title
+	}
+
+	return fmt.Sprintf("%s %s", glyph, title)
+}
+
+// selectedArchive returns the path of the archive under the cursor of the
+// filetree, or an empty string if the selected item isn't one.
+func (b Bubble) selectedArchive() string {
+	selectedFile := b.filetree.GetSelectedItem()
+	if b.activeBox != 0 || selectedFile.IsDirectory() || !filesystem.IsArchive(selectedFile.FileName()) {
+		return ""
+	}
+
+	return selectedFile.FileName()
+}
+
+// openArchive starts listing the root of an archive in the picker.
+func (b *Bubble) openArchive(path string) tea.Cmd {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	b.archive = &archiveBrowser{path: absPath}
+
+	return listArchiveDirCmd(absPath, "", b.config.Settings.CaseSensitiveSort)
+}
+
+// closeArchive leaves the browsed archive, focusing the filetree.
+func (b *Bubble) closeArchive() {
+	b.archive = nil
+	b.closePicker()
+}
+
+// showArchiveDir lists the entries of a directory within the browsed archive
+// in the picker, directories first and ending in a slash.
+func (b *Bubble) showArchiveDir(msg archiveDirMsg) []tea.Cmd {
+	if b.archive == nil || msg.path != b.archive.path {
+		return nil
+	}
+
+	if msg.err != nil {
+		b.closeArchive()
+		return []tea.Cmd{b.setStatusMessage(fmt.Sprintf("Unable to read archive: %v", msg.err))}
+	}
+
+	b.archive.dir = msg.dir
+
+	items := make([]picker.Item, 0, len(msg.items))
+	for _, item := range msg.items {
+		if item.IsDir {
+			items = append(items, picker.Item{Title: withGlyph(b.iconSet.Directory(), item.Name+"/"), Value: item.Path + "/"})
+			continue
+		}
+
+		items = append(items, picker.Item{
+			Title: withGlyph(b.iconSet.File(), fmt.Sprintf("%s  %s", item.Name, strfmt.FormatSize(item.Size, b.sizeFormat))),
+			Value: item.Path,
+		})
+	}
+
+	title := filepath.Base(msg.path)
+	if msg.dir != "" {
+		title = fmt.Sprintf("%s/%s", title, msg.dir)
+	}
+
+	cmds := b.setPreviewVisible(true)
+	b.openPicker(archivePicker, title, items)
+
+	return cmds
+}
+
+// openArchiveItem lists a directory within the browsed archive or previews a
+// file within it, which is read without extracting it to disk.
+func (b *Bubble) openArchiveItem(value string) tea.Cmd {
+	if strings.HasSuffix(value, "/") {
+		return listArchiveDirCmd(b.archive.path, strings.TrimSuffix(value, "/"), b.config.Settings.CaseSensitiveSort)
+	}
+
+	b.preview.isImage = false
+	b.preview.isMarkdown = false
+	b.preview.isTable = false
+	b.preview.isHex = false
+	b.renderer.SetLineNumbers(false)
+	b.resetViewports()
+	b.state = showTextState
+	b.preview.path = b.archive.entryPath(value)
+	b.activeBox = 1
+	b.updateActiveBox()
+
+	style := ""
+	if b.config.Settings.SyntaxHighlighting {
+		style = syntaxTheme(b.config)
+	}
+
+	width, _ := b.renderer.Size()
+
+	return tea.Batch(b.trackPreview(readArchiveEntryCmd(*b.archive, value, b.config.Settings.MaxPreviewBytes, style, width))...)
+}
+
+// archiveEntryPreviewed returns true if the focused preview shows a file
+// within the browsed archive.
+func (b Bubble) archiveEntryPreviewed() bool {
+	return b.archive != nil &&
+		b.activeBox == 1 &&
+		b.state == showTextState &&
+		strings.HasPrefix(b.preview.path, b.archive.path+string(filepath.Separator))
+}
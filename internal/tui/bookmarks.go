@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/knipferrc/fm/internal/bookmarks"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type bookmarksMsg []bookmarks.Bookmark
+
+// listBookmarksCmd reads the list of bookmarks.
+func listBookmarksCmd() tea.Cmd {
+	return func() tea.Msg {
+		list, err := bookmarks.List()
+		if err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return bookmarksMsg(list)
+	}
+}
+
+// addBookmarkCmd bookmarks a directory under the given name.
+func addBookmarkCmd(name, dir string) tea.Cmd {
+	return func() tea.Msg {
+		if err := bookmarks.Add(name, dir); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return statusMessageMsg(fmt.Sprintf("Bookmarked %s", dir))
+	}
+}
+
+// removeBookmarkCmd removes the bookmark of a directory and returns the remaining bookmarks.
+func removeBookmarkCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		if err := bookmarks.Remove(dir); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return listBookmarksCmd()()
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// breadcrumbHeight is the number of lines used by the breadcrumb.
+	breadcrumbHeight = 1
+
+	// breadcrumbSeparator is placed between the segments of the breadcrumb.
+	breadcrumbSeparator = " › "
+
+	// breadcrumbEllipsis replaces the segments left out of a long breadcrumb.
+	breadcrumbEllipsis = "…"
+)
+
+// breadcrumbSegments splits a directory into its path segments, showing
+// the home directory as ~.
+func breadcrumbSegments(dir string) []string {
+	var prefix string
+	if home, err := os.UserHomeDir(); err == nil {
+		if dir == home || strings.HasPrefix(dir, home+string(filepath.Separator)) {
+			prefix = "~"
+			dir = strings.TrimPrefix(dir, home)
+		}
+	}
+
+	if prefix == "" {
+		prefix = filepath.VolumeName(dir) + string(filepath.Separator)
+		dir = strings.TrimPrefix(dir, filepath.VolumeName(dir))
+	}
+
+	segments := []string{prefix}
+	for _, segment := range strings.Split(dir, string(filepath.Separator)) {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// breadcrumbView returns the path of the current directory fitting within
+// the given width, leaving out segments from the middle when it is too long.
+func (b Bubble) breadcrumbView(width int) string {
+	segments := breadcrumbSegments(b.currentDirectory())
+
+	segmentStyle := lipgloss.NewStyle().Foreground(b.theme.UnselectedTreeItemColor)
+	lastSegmentStyle := lipgloss.NewStyle().Bold(true).Foreground(b.theme.SelectedTreeItemColor)
+
+	render := func(segments []string) string {
+		rendered := make([]string, len(segments))
+		for i, segment := range segments {
+			if i == len(segments)-1 {
+				rendered[i] = lastSegmentStyle.Render(segment)
+			} else {
+				rendered[i] = segmentStyle.Render(segment)
+			}
+		}
+
+		return strings.Join(rendered, segmentStyle.Render(breadcrumbSeparator))
+	}
+
+	view := render(segments)
+	for skip := 1; lipgloss.Width(view) > width && len(segments)-skip > 2; skip++ {
+		shortened := append([]string{segments[0], breadcrumbEllipsis}, segments[skip+1:]...)
+		view = render(shortened)
+	}
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Render(truncate.StringWithTail(view, uint(width), breadcrumbEllipsis))
+}
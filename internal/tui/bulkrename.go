@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulkRenamePreviewLines is the number of renames listed when asking to confirm a bulk rename.
+const bulkRenamePreviewLines = 8
+
+// bulkRenameSources returns the selected items, or the files in the current
+// directory which aren't hidden if nothing is selected.
+func (b Bubble) bulkRenameSources() ([]string, error) {
+	if len(b.selection) > 0 {
+		paths := b.selectedPaths()
+		for i, path := range paths {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+
+			paths[i] = absPath
+		}
+
+		return paths, nil
+	}
+
+	dir := b.currentDirectory()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+
+	return paths, nil
+}
+
+// startBulkRename focuses the input to pick the pattern the selected items,
+// or the files in the current directory, are renamed with.
+func (b *Bubble) startBulkRename() tea.Cmd {
+	paths, err := b.bulkRenameSources()
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if len(paths) == 0 {
+		return b.setStatusMessage("Nothing to rename")
+	}
+
+	b.prompt.bulkRenamePaths = paths
+	b.startInput(bulkRenameInputMode, fmt.Sprintf("Rename %d items to: ", len(paths)), "{name}{ext}")
+
+	return nil
+}
+
+// confirmBulkRename lists the new names the pattern gives the items, asking
+// before renaming them. Collisions are reported instead.
+func (b *Bubble) confirmBulkRename(pattern string) tea.Cmd {
+	pairs, err := filesystem.ApplyRenamePattern(pattern, b.prompt.bulkRenamePaths)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if err := filesystem.CheckRenamePairs(pairs); err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	var changed []filesystem.RenamePair
+	for _, pair := range pairs {
+		if pair.Src != pair.Dst {
+			changed = append(changed, pair)
+		}
+	}
+
+	if len(changed) == 0 {
+		return b.setStatusMessage("The pattern keeps every name")
+	}
+
+	lines := []string{fmt.Sprintf("Rename %d items?", len(changed)), ""}
+	for i, pair := range changed {
+		if i == bulkRenamePreviewLines {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(changed)-i))
+			break
+		}
+
+		lines = append(lines, fmt.Sprintf("%s → %s", filepath.Base(pair.Src), filepath.Base(pair.Dst)))
+	}
+
+	b.confirm("Bulk rename", strings.Join(lines, "\n"), func(b *Bubble) tea.Cmd {
+		b.clearSelection()
+
+		return bulkRenameCmd(changed)
+	})
+
+	return nil
+}
+
+// bulkRenameCmd renames all items at once, leaving them as they were if
+// one of them can't be renamed.
+func bulkRenameCmd(pairs []filesystem.RenamePair) tea.Cmd {
+	return func() tea.Msg {
+		if err := filesystem.RenameAll(pairs); err != nil {
+			return fileOperationMsg{message: fmt.Sprintf("Renamed nothing: %v", err)}
+		}
+
+		return fileOperationMsg{
+			message:    fmt.Sprintf("Renamed %d items", len(pairs)),
+			selectPath: pairs[0].Dst,
+			undo:       bulkRenameUndo(pairs),
+		}
+	}
+}
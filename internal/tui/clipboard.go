@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fileClipboard represents the items marked to be pasted into another directory.
+type fileClipboard struct {
+	paths []string
+	cut   bool
+}
+
+// verb returns what pasting the items does to them.
+func (c fileClipboard) verb() string {
+	if c.cut {
+		return "move"
+	}
+
+	return "copy"
+}
+
+// markForPaste marks the selected items, or the item under the cursor if
+// nothing is selected, to be copied, or moved if cut is true, into the
+// directory they are pasted in.
+func (b *Bubble) markForPaste(cut bool) tea.Cmd {
+	paths, err := absPaths(b.selectedPaths())
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	b.clipboard = &fileClipboard{paths: paths, cut: cut}
+	b.clearSelection()
+
+	return b.setStatusMessage(fmt.Sprintf(
+		"Marked %s to %s, press %s to paste",
+		itemsSubject(paths),
+		b.clipboard.verb(),
+		bindingKeys(b.keys.Paste),
+	))
+}
+
+// paste copies or moves the marked items into the current directory. An
+// item copied into its own directory is copied next to itself under the
+// first free "name (n)" name. Moved items are no longer marked once they
+// are moved.
+func (b *Bubble) paste() tea.Cmd {
+	if b.clipboard == nil {
+		return b.setStatusMessage("Nothing to paste")
+	}
+
+	dir := b.currentDirectory()
+	transfers := make([]transfer, 0, len(b.clipboard.paths))
+
+	for _, src := range b.clipboard.paths {
+		dst := filepath.Join(dir, filepath.Base(src))
+
+		if b.clipboard.cut {
+			if dst != src {
+				transfers = append(transfers, transfer{src: src, dst: dst, mode: moveTransfer})
+			}
+
+			continue
+		}
+
+		if dst == src {
+			info, err := os.Lstat(src)
+			dst = duplicateName(src, err == nil && info.IsDir())
+		}
+
+		transfers = append(transfers, transfer{src: src, dst: dst, mode: copyTransfer})
+	}
+
+	if len(transfers) == 0 {
+		return b.setStatusMessage(fmt.Sprintf("%s already in this directory", itemsSubject(b.clipboard.paths)))
+	}
+
+	if b.clipboard.cut {
+		b.clipboard = nil
+	}
+
+	return b.transferItems(transfers)
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fileOperationMsg struct {
+	message    string
+	selectPath string
+	undo       *undoAction
+}
+
+// copyToClipboardCmd copies the absolute path of a directory item to the
+// clipboard, or the contents of a text file when contents is true.
+func copyToClipboardCmd(path string, contents bool) tea.Cmd {
+	return func() tea.Msg {
+		if contents {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return statusMessageMsg(err.Error())
+			}
+
+			if !utf8.Valid(data) {
+				return statusMessageMsg("Only the contents of text files can be copied")
+			}
+
+			if err := clipboard.WriteAll(string(data)); err != nil {
+				return statusMessageMsg(fmt.Sprintf("Unable to copy to clipboard: %v", err))
+			}
+
+			return statusMessageMsg("Copied contents to clipboard")
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		if err := clipboard.WriteAll(absPath); err != nil {
+			return statusMessageMsg(fmt.Sprintf("Unable to copy to clipboard: %v", err))
+		}
+
+		return statusMessageMsg("Copied path to clipboard")
+	}
+}
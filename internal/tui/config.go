@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/finder"
+	"github.com/knipferrc/fm/internal/keyhelp"
+	"github.com/knipferrc/fm/internal/modal"
+	"github.com/knipferrc/fm/internal/picker"
+	"github.com/knipferrc/fm/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/knipferrc/teacup/statusbar"
+)
+
+// reloadConfig reloads the config file and updates the UI.
+func (b *Bubble) reloadConfig() []tea.Cmd {
+	var cmds []tea.Cmd
+
+	cfg, err := config.ParseConfig()
+	if err != nil {
+		message := strings.SplitN(err.Error(), "\n", 2)[0]
+		return []tea.Cmd{b.setStatusMessage(fmt.Sprintf("Kept the current config: %s", message))}
+	}
+
+	if cfg.Settings.SplitRatio != b.config.Settings.SplitRatio {
+		b.splitRatio = clampSplitRatio(cfg.Settings.SplitRatio)
+	}
+
+	b.config = cfg
+	b.sizeFormat = sizeFormat(cfg)
+	b.dirs.diskSpaceDir = ""
+
+	keys, warnings := NewKeyMap(cfg.Keybindings)
+	b.keys = keys
+	if len(warnings) > 0 {
+		cmds = append(cmds, b.setStatusMessage(warnings[0]))
+	}
+
+	b.code.SetSyntaxTheme(syntaxTheme(cfg))
+	b.applyTheme(theme.GetTheme(cfg.Theme.AppTheme))
+	b.picker.SetScrollOff(cfg.Settings.ScrollOff)
+	b.finder.SetScrollOff(cfg.Settings.ScrollOff)
+
+	if showsClock(cfg) && !b.segment.clockTicking {
+		b.segment.clockTicking = true
+		cmds = append(cmds, clockTickCmd())
+	}
+
+	b.iconSet = iconSet(cfg)
+	cmds = append(cmds, b.filetree.ToggleShowIcons(b.iconSet.TreeIcons()))
+
+	b.filetree.SetBorderless(cfg.Settings.Borderless)
+	b.code.SetBorderless(cfg.Settings.Borderless)
+	b.image.SetBorderless(cfg.Settings.Borderless)
+	b.renderer.SetBorderless(cfg.Settings.Borderless)
+	b.renderer.SetWrap(cfg.Settings.WrapPreview)
+	b.picker.SetBorderless(cfg.Settings.Borderless)
+
+	return append(cmds, b.resize()...)
+}
+
+// applyTheme sets the colors of all components from a theme.
+func (b *Bubble) applyTheme(theme theme.Theme) {
+	b.theme = theme
+	b.statusbar.SetColors(
+		statusbar.ColorConfig{
+			Foreground: theme.StatusBarSelectedFileForegroundColor,
+			Background: theme.StatusBarSelectedFileBackgroundColor,
+		},
+		statusbar.ColorConfig{
+			Foreground: theme.StatusBarBarForegroundColor,
+			Background: theme.StatusBarBarBackgroundColor,
+		},
+		statusbar.ColorConfig{
+			Foreground: theme.StatusBarTotalFilesForegroundColor,
+			Background: theme.StatusBarTotalFilesBackgroundColor,
+		},
+		statusbar.ColorConfig{
+			Foreground: theme.StatusBarLogoForegroundColor,
+			Background: theme.StatusBarLogoBackgroundColor,
+		},
+	)
+
+	b.help = newHelp(b.config, theme, b.keys)
+
+	b.filetree.SetTitleColors(theme.TitleForegroundColor, theme.TitleBackgroundColor)
+	b.filetree.SetSelectedItemColors(theme.SelectedTreeItemColor)
+	b.renderer.SetLineNumberColor(theme.LineNumberColor)
+
+	b.picker.SetSelectedItemColor(theme.SelectedTreeItemColor)
+	b.picker.SetTitleColor(
+		picker.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+	)
+
+	b.modal.SetBorderColor(theme.ActiveBoxBorderColor)
+	b.modal.SetTitleColor(
+		modal.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+	)
+
+	b.keyHelp.SetBorderColor(theme.ActiveBoxBorderColor)
+	b.keyHelp.SetHeadingColor(theme.SelectedTreeItemColor)
+	b.keyHelp.SetTitleColor(
+		keyhelp.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+	)
+	b.keyHelp.SetGroups(b.keys.helpGroups())
+
+	b.finder.SetBorderColor(theme.ActiveBoxBorderColor)
+	b.finder.SetSelectedItemColor(theme.SelectedTreeItemColor)
+	b.finder.SetTitleColor(
+		finder.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+	)
+
+	b.updateActiveBox()
+}
+
+// cycleTheme switches to the next bundled theme for the rest of the session.
+func (b *Bubble) cycleTheme() []tea.Cmd {
+	names := theme.Names()
+	next := names[0]
+	for i, name := range names {
+		if name == b.config.Theme.AppTheme && i+1 < len(names) {
+			next = names[i+1]
+		}
+	}
+
+	b.config.Theme.AppTheme = next
+	b.applyTheme(theme.GetTheme(next))
+
+	// The help box is recreated with the new colors and needs its size again.
+	return append(b.resize(), b.setStatusMessage(fmt.Sprintf("Theme: %s", next)))
+}
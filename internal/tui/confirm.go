@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmAction is run once the user confirmed it in the modal.
+type confirmAction func(b *Bubble) tea.Cmd
+
+// choice represents an answer offered by the modal, picked with its key.
+type choice struct {
+	key    string
+	label  string
+	action confirmAction
+}
+
+// confirmation is the action waiting on the answer of the modal shown.
+type confirmation struct {
+	action  confirmAction
+	choices []choice
+}
+
+// confirm shows a modal asking to confirm an action, running it once confirmed.
+func (b *Bubble) confirm(title, message string, action confirmAction) {
+	b.confirmation.action = action
+	b.confirmation.choices = nil
+	b.modal.Show(title, message)
+}
+
+// choose shows a modal offering several answers, running the action of the
+// one picked.
+func (b *Bubble) choose(title, message string, choices []choice) {
+	hints := make([]string, 0, len(choices)+1)
+	for _, c := range choices {
+		hints = append(hints, fmt.Sprintf("%s %s", c.key, c.label))
+	}
+
+	b.confirmation.action = nil
+	b.confirmation.choices = choices
+	b.modal.ShowWithHint(title, message, strings.Join(append(hints, "esc cancel"), " • "))
+}
+
+// handleConfirmKey runs the pending action when confirmed with enter, or the
+// action of the answer picked when choosing, and drops it when cancelled
+// with esc, ignoring any other key.
+func (b *Bubble) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	var action confirmAction
+
+	switch {
+	case key.Matches(msg, b.keys.Cancel):
+	case b.confirmation.choices != nil:
+		for _, c := range b.confirmation.choices {
+			if msg.String() == c.key {
+				action = c.action
+			}
+		}
+
+		if action == nil {
+			return nil
+		}
+	case key.Matches(msg, b.keys.Submit):
+		action = b.confirmation.action
+	default:
+		return nil
+	}
+
+	b.confirmation.action = nil
+	b.confirmation.choices = nil
+	b.modal.Hide()
+
+	if action != nil {
+		return action(b)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxCount is the largest count a motion can be repeated by.
+const maxCount = 9999
+
+var (
+	cursorDownKey = key.NewBinding(key.WithKeys("down", "j"))
+	cursorUpKey   = key.NewBinding(key.WithKeys("up", "k"))
+)
+
+// countDigit returns the digit a key adds to the pending count of the next
+// motion, a leading zero not being a count.
+func (b Bubble) countDigit(msg tea.KeyMsg) (int, bool) {
+	if b.activeBox != 0 || msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return 0, false
+	}
+
+	r := msg.Runes[0]
+	if r < '0' || r > '9' || (r == '0' && b.count == 0) {
+		return 0, false
+	}
+
+	return int(r - '0'), true
+}
+
+// countMotion returns the number of items a motion moves the cursor by,
+// which is repeated by the pending count.
+func (b Bubble) countMotion(msg tea.KeyMsg) (int, bool) {
+	switch {
+	case key.Matches(msg, cursorDownKey):
+		return 1, true
+	case key.Matches(msg, cursorUpKey):
+		return -1, true
+	case key.Matches(msg, b.keys.HalfPageDown):
+		return b.filetreePageSize() / 2, true
+	case key.Matches(msg, b.keys.HalfPageUp):
+		return -b.filetreePageSize() / 2, true
+	case key.Matches(msg, b.keys.PageDown):
+		return b.filetreePageSize(), true
+	case key.Matches(msg, b.keys.PageUp):
+		return -b.filetreePageSize(), true
+	}
+
+	return 0, false
+}
+
+// ownsCountKey returns true if a key extends the pending count or is a
+// motion repeated by it.
+func (b Bubble) ownsCountKey(msg tea.KeyMsg) bool {
+	if _, ok := b.countDigit(msg); ok {
+		return true
+	}
+
+	_, ok := b.countMotion(msg)
+
+	return ok && b.count > 0
+}
+
+// handleCountKey adds digits to the pending count and repeats the next
+// motion by it, returning false if the key should be handled as usual.
+// Any other key clears the pending count.
+func (b *Bubble) handleCountKey(msg tea.KeyMsg) bool {
+	if digit, ok := b.countDigit(msg); ok {
+		b.count = b.count*10 + digit
+		if b.count > maxCount {
+			b.count = maxCount
+		}
+
+		return true
+	}
+
+	if b.count == 0 {
+		return false
+	}
+
+	count := b.count
+	b.count = 0
+
+	if delta, ok := b.countMotion(msg); ok {
+		b.moveCursor(delta * count)
+		return true
+	}
+
+	return false
+}
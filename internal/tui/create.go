@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/templates"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type templatesMsg []templates.Template
+
+type clipboardTextMsg string
+
+// createItemCmd creates a new file or directory in the given directory.
+func createItemCmd(dir, name string, isDirectory bool) tea.Cmd {
+	return func() tea.Msg {
+		if name == "" {
+			return statusMessageMsg("A name is required")
+		}
+
+		path := filepath.Join(dir, name)
+
+		create := filesystem.CreateFile
+		if isDirectory {
+			create = filesystem.CreateDirectory
+		}
+
+		if err := create(path); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return fileOperationMsg{message: fmt.Sprintf("Created %s", name), selectPath: path}
+	}
+}
+
+// listTemplatesCmd lists the templates new files can be created from.
+func listTemplatesCmd() tea.Cmd {
+	return func() tea.Msg {
+		list, err := templates.List()
+		if err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return templatesMsg(list)
+	}
+}
+
+// createFromTemplateCmd creates a new file from a template in the given directory.
+func createFromTemplateCmd(dir, name, template string) tea.Cmd {
+	return func() tea.Msg {
+		if name == "" {
+			return statusMessageMsg("A name is required")
+		}
+
+		path := filepath.Join(dir, name)
+		if err := filesystem.CreateFromTemplate(path, template); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return fileOperationMsg{
+			message:    fmt.Sprintf("Created %s from %s", name, filepath.Base(template)),
+			selectPath: path,
+		}
+	}
+}
+
+// readClipboardCmd reads the text on the clipboard to create a file from,
+// refusing an empty clipboard or one holding something other than text.
+func readClipboardCmd() tea.Cmd {
+	return func() tea.Msg {
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			return statusMessageMsg(fmt.Sprintf("Unable to read the clipboard: %v", err))
+		}
+
+		if text == "" {
+			return statusMessageMsg("The clipboard is empty")
+		}
+
+		if !utf8.ValidString(text) || strings.ContainsRune(text, 0) {
+			return statusMessageMsg("Only text on the clipboard can be saved to a file")
+		}
+
+		return clipboardTextMsg(text)
+	}
+}
+
+// createFromClipboardCmd creates a new file holding text from the clipboard.
+func createFromClipboardCmd(dir, name, text string) tea.Cmd {
+	return func() tea.Msg {
+		if name == "" {
+			return statusMessageMsg("A name is required")
+		}
+
+		path := filepath.Join(dir, name)
+		if err := filesystem.CreateFileWithContent(path, []byte(text)); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return fileOperationMsg{
+			message:    fmt.Sprintf("Created %s from the clipboard", name),
+			selectPath: path,
+		}
+	}
+}
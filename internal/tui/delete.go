@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deleteSelectedItems asks for confirmation before deleting the selected
+// items, or the item under the cursor if nothing is selected. Without
+// confirm_delete they are always moved to the trash right away, whatever
+// use_trash is set to, so that it can be undone. Platforms without a trash
+// still ask first.
+func (b *Bubble) deleteSelectedItems() tea.Cmd {
+	paths := b.selectedPaths()
+	if len(paths) == 0 {
+		return nil
+	}
+
+	if !b.config.Settings.ConfirmDelete && filesystem.TrashSupported {
+		b.clearSelection()
+
+		return deleteItemsCmd(paths, true)
+	}
+
+	message := fmt.Sprintf("Delete %s?", filepath.Base(paths[0]))
+	if len(paths) > 1 {
+		message = fmt.Sprintf("Delete %d items?", len(paths))
+	}
+
+	useTrash := b.config.Settings.UseTrash
+	title := "Delete"
+	switch {
+	case useTrash && filesystem.TrashSupported:
+		title = "Move to trash"
+	case useTrash:
+		title = "Delete, trash is not supported"
+	}
+
+	b.confirm(title, message, func(b *Bubble) tea.Cmd {
+		b.clearSelection()
+
+		return deleteItemsCmd(paths, useTrash)
+	})
+
+	return nil
+}
+
+// deleteItem deletes a file or directory, moving it to the trash instead when
+// useTrash is set and the platform supports it. It returns the path the item
+// was moved to in the trash, which is empty if it was deleted permanently.
+func deleteItem(path string, useTrash bool) (string, error) {
+	if useTrash {
+		trashPath, err := filesystem.TrashFile(path)
+		if err == nil {
+			return trashPath, nil
+		}
+
+		if !errors.Is(err, filesystem.ErrTrashUnsupported) {
+			return "", err
+		}
+	}
+
+	return "", os.RemoveAll(path)
+}
+
+// deleteItemsCmd deletes the given files and directories, going on with the
+// rest if one of them can't be deleted and reporting the failures at the end.
+// Deleting them can be undone if they were all moved to the trash.
+func deleteItemsCmd(paths []string, useTrash bool) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			deleted []string
+			trashed []trashedItem
+			failed  []error
+		)
+
+		for _, path := range paths {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				failed = append(failed, err)
+				continue
+			}
+
+			trashPath, err := deleteItem(absPath, useTrash)
+			if err != nil {
+				logger.Error("deleting failed", "path", absPath, "err", err)
+				failed = append(failed, err)
+
+				continue
+			}
+
+			deleted = append(deleted, absPath)
+			if trashPath != "" {
+				trashed = append(trashed, trashedItem{trashPath: trashPath, originalPath: absPath})
+			}
+		}
+
+		if len(deleted) == 0 {
+			if len(failed) == 1 {
+				return fileOperationMsg{message: failed[0].Error()}
+			}
+
+			return fileOperationMsg{message: fmt.Sprintf("Deleted nothing, %d items failed: %v", len(failed), failed[0])}
+		}
+
+		subject := filepath.Base(deleted[0])
+		if len(deleted) > 1 {
+			subject = fmt.Sprintf("%d items", len(deleted))
+		}
+
+		var msg fileOperationMsg
+		switch {
+		case len(trashed) == len(deleted):
+			msg = fileOperationMsg{
+				message: fmt.Sprintf("Moved %s to the trash", subject),
+				undo:    trashUndo(trashed),
+			}
+		case useTrash:
+			msg = fileOperationMsg{message: fmt.Sprintf("Trash is not supported on this platform, permanently deleted %s", subject)}
+		default:
+			msg = fileOperationMsg{message: fmt.Sprintf("Deleted %s", subject)}
+		}
+
+		if len(failed) > 0 {
+			msg.message = fmt.Sprintf("%s, %d failed: %v", msg.message, len(failed), failed[0])
+		}
+
+		return msg
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type editorFinishedMsg struct {
+	err error
+}
+
+type shellFinishedMsg struct {
+	err error
+}
+
+// refreshAfterExec lists the current directory again after an editor or a
+// shell exited, dropping what is cached about it as files may have changed.
+func (b *Bubble) refreshAfterExec() tea.Cmd {
+	b.dirs.forgetEntries()
+
+	return b.refreshFiletree()
+}
+
+// editorCommand returns the command used to edit files, preferring the
+// configured editor, then $EDITOR and finally vi or nano.
+func editorCommand(configuredEditor string) []string {
+	for _, editor := range []string{configuredEditor, os.Getenv("EDITOR")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if _, err := exec.LookPath("vi"); err == nil {
+		return []string{"vi"}
+	}
+
+	return []string{"nano"}
+}
+
+// openEditorCmd suspends the UI and opens a file in the editor.
+func openEditorCmd(path, configuredEditor string) tea.Cmd {
+	editor := editorCommand(configuredEditor)
+	args := append(editor[1:], path)
+
+	editorCmd := exec.Command(editor[0], args...)
+
+	return tea.ExecProcess(editorCmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// shellCommand returns the command used to open a shell, preferring the
+// configured shell, then $SHELL and finally the default shell of the platform.
+func shellCommand(configuredShell string) []string {
+	for _, shell := range []string{configuredShell, os.Getenv("SHELL")} {
+		if fields := strings.Fields(shell); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return []string{comspec}
+		}
+
+		return []string{"cmd"}
+	}
+
+	return []string{"sh"}
+}
+
+// openShellCmd suspends the UI and opens a shell in the given directory.
+func openShellCmd(dir, configuredShell string) tea.Cmd {
+	shell := shellCommand(configuredShell)
+
+	shellCmd := exec.Command(shell[0], shell[1:]...)
+	shellCmd.Dir = dir
+
+	return tea.ExecProcess(shellCmd, func(err error) tea.Msg {
+		return shellFinishedMsg{err: err}
+	})
+}
+
+// openWithDefaultAppCmd opens a file in the system's default application.
+func openWithDefaultAppCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := filesystem.OpenWithDefaultApp(path); err != nil {
+			return statusMessageMsg(fmt.Sprintf("Failed to open %s: %v", filepath.Base(path), err))
+		}
+
+		return nil
+	}
+}
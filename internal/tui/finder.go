@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFinderPaths is the number of files indexed for the finder.
+const maxFinderPaths = 100000
+
+// fileIndex represents the files below a directory being listed for the finder.
+type fileIndex struct {
+	root   string
+	ch     chan tea.Msg
+	cancel context.CancelFunc
+}
+
+type finderPathsMsg struct {
+	ch    chan tea.Msg
+	paths []string
+	done  bool
+}
+
+// openFinder shows the finder and starts listing the files below the current
+// directory, cancelling the listing of a previous one.
+func (b *Bubble) openFinder() tea.Cmd {
+	b.cancelFileIndex()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.fileIndex = &fileIndex{
+		root:   b.currentDirectory(),
+		ch:     make(chan tea.Msg, 1),
+		cancel: cancel,
+	}
+	b.finder.Show()
+
+	opts := filesystem.IndexOptions{
+		ShowHidden:       b.config.Settings.FinderShowHidden,
+		RespectGitignore: b.config.Settings.FinderGitignore,
+		Limit:            maxFinderPaths,
+	}
+
+	return indexFilesCmd(ctx, b.fileIndex.root, opts, b.fileIndex.ch)
+}
+
+// cancelFileIndex stops listing files for the finder.
+func (b *Bubble) cancelFileIndex() {
+	if b.fileIndex != nil {
+		b.fileIndex.cancel()
+		b.fileIndex = nil
+	}
+}
+
+// handleFinderKey jumps to the file under the cursor of the finder when
+// submitted and closes it when cancelled, passing any other key on to it.
+func (b *Bubble) handleFinderKey(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, b.keys.Quit):
+		b.finder.Hide()
+		b.cancelFileIndex()
+
+		return b.quit()
+	case key.Matches(msg, b.keys.Cancel):
+		b.finder.Hide()
+		b.cancelFileIndex()
+	case key.Matches(msg, b.keys.Submit):
+		path, ok := b.finder.SelectedPath()
+		if !ok {
+			return nil
+		}
+
+		path = filepath.Join(b.fileIndex.root, path)
+		b.finder.Hide()
+		b.cancelFileIndex()
+
+		b.pendingSelection = pendingSelection{
+			dir:     filepath.Dir(path),
+			name:    filepath.Base(path),
+			preview: true,
+		}
+
+		return b.navigateTo(filepath.Dir(path))
+	default:
+		b.finder, cmd = b.finder.Update(msg)
+	}
+
+	return cmd
+}
+
+// indexFilesCmd lists the files below root in the background, sending them
+// on ch in batches until done or cancelled.
+func indexFilesCmd(ctx context.Context, root string, opts filesystem.IndexOptions, ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		go func() {
+			defer close(ch)
+
+			send := func(msg tea.Msg) bool {
+				select {
+				case ch <- msg:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			err := filesystem.IndexFiles(ctx, root, opts, func(paths []string) {
+				send(finderPathsMsg{ch: ch, paths: paths})
+			})
+			if err != nil && ctx.Err() == nil {
+				logger.Error("indexing files failed", "root", root, "err", err)
+			}
+
+			send(finderPathsMsg{ch: ch, done: true})
+		}()
+
+		return waitForFinderCmd(ch)()
+	}
+}
+
+// waitForFinderCmd waits for the next batch of indexed files.
+func waitForFinderCmd(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+
+		return msg
+	}
+}
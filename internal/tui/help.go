@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/keyhelp"
+	"github.com/knipferrc/fm/internal/theme"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/knipferrc/teacup/help"
+)
+
+// keyNames maps keys to the name they are shown with in the help.
+var keyNames = map[string]string{
+	" ": "space",
+}
+
+// bindingKeys returns the keys of a binding as shown in the help.
+func bindingKeys(binding key.Binding) string {
+	keys := make([]string, 0, len(binding.Keys()))
+	for _, k := range binding.Keys() {
+		if name, ok := keyNames[k]; ok {
+			k = name
+		}
+
+		keys = append(keys, k)
+	}
+
+	return strings.Join(keys, ", ")
+}
+
+// helpGroups returns the keybindings grouped by category, showing the keys
+// currently bound to each action. Keys handled by the filetree itself can't
+// be changed and are listed as they are.
+func (k KeyMap) helpGroups() []keyhelp.Group {
+	return []keyhelp.Group{
+		{
+			Title: "Navigation",
+			Entries: []keyhelp.Entry{
+				{Key: "k, up", Description: "Move up"},
+				{Key: "j, down", Description: "Move down"},
+				{Key: "h, l", Description: "Paginate left or right in the current directory"},
+				{Key: "g, G", Description: "Jump to the top or bottom"},
+				{Key: bindingKeys(k.HalfPageDown), Description: "Move down half a page"},
+				{Key: bindingKeys(k.HalfPageUp), Description: "Move up half a page"},
+				{Key: bindingKeys(k.PageDown), Description: "Move down a page"},
+				{Key: bindingKeys(k.PageUp), Description: "Move up a page"},
+				{Key: "5j", Description: "Prefix a motion with a count to repeat it"},
+				{Key: bindingKeys(k.OpenFile), Description: "Read file or enter directory"},
+				{Key: bindingKeys(k.ParentDirectory), Description: "Go to the parent directory"},
+				{Key: bindingKeys(k.Submit), Description: "Browse the entries of a zip or tar archive"},
+				{Key: "~", Description: "Go to the home directory"},
+				{Key: "R", Description: "Go to the root directory"},
+				{Key: bindingKeys(k.GoToStartDir), Description: "Go to the start directory"},
+				{Key: bindingKeys(k.GoToPath), Description: "Go to a path, tab completes directories"},
+				{Key: bindingKeys(k.Find), Description: "Find a file below the current directory"},
+				{Key: "/", Description: "Filter the current directory"},
+				{Key: bindingKeys(k.AddBookmark), Description: "Bookmark the current directory"},
+				{Key: bindingKeys(k.ShowBookmarks), Description: "Show bookmarks"},
+				{Key: bindingKeys(k.ShowMounts), Description: "Show mounted filesystems or drives"},
+				{Key: bindingKeys(k.NewTab), Description: "Open a new tab"},
+				{Key: bindingKeys(k.CloseTab), Description: "Close the current tab"},
+				{Key: bindingKeys(k.NextTab), Description: "Go to the next tab"},
+				{Key: bindingKeys(k.PreviousTab), Description: "Go to the previous tab"},
+			},
+		},
+		{
+			Title: "File operations",
+			Entries: []keyhelp.Entry{
+				{Key: bindingKeys(k.Select), Description: "Toggle selection of the item under the cursor"},
+				{Key: bindingKeys(k.CreateFile), Description: "Create a new file"},
+				{Key: bindingKeys(k.NewFromTemplate), Description: "Create a new file from a template"},
+				{Key: bindingKeys(k.FromClipboard), Description: "Create a new file holding the text on the clipboard"},
+				{Key: bindingKeys(k.CreateDirectory), Description: "Create a new directory"},
+				{Key: bindingKeys(k.Rename), Description: "Rename the selected item"},
+				{Key: bindingKeys(k.BulkRename), Description: "Rename the selected items, or all files, with a pattern"},
+				{Key: bindingKeys(k.Move), Description: "Move the selected items, tab completes directories"},
+				{Key: bindingKeys(k.Copy), Description: "Mark the selected items to be copied"},
+				{Key: bindingKeys(k.Cut), Description: "Mark the selected items to be moved"},
+				{Key: bindingKeys(k.Paste), Description: "Paste the marked items into the current directory"},
+				{Key: bindingKeys(k.Duplicate), Description: "Duplicate the selected items in place"},
+				{Key: bindingKeys(k.SaveAs), Description: "Save a copy of the selected file under a new name"},
+				{Key: bindingKeys(k.Delete), Description: "Delete the selected items"},
+				{Key: bindingKeys(k.Undo), Description: "Undo the last rename, move or move to the trash"},
+				{Key: "z", Description: "Zip the selected item"},
+				{Key: "u", Description: "Unzip the selected item"},
+				{Key: bindingKeys(k.Edit), Description: "Edit the selected file"},
+				{Key: bindingKeys(k.OpenShell), Description: "Open a shell in the current directory"},
+				{Key: bindingKeys(k.OpenWith), Description: "Open in the default application"},
+				{Key: bindingKeys(k.CopyPath), Description: "Copy the path to the clipboard"},
+				{Key: bindingKeys(k.CopyContents), Description: "Copy the file contents to the clipboard"},
+			},
+		},
+		{
+			Title: "View",
+			Entries: []keyhelp.Entry{
+				{Key: bindingKeys(k.ToggleBox), Description: "Toggle between boxes"},
+				{Key: bindingKeys(k.TogglePreview), Description: "Show or hide the preview"},
+				{Key: bindingKeys(k.ShrinkTree), Description: "Move the divider towards the file tree"},
+				{Key: bindingKeys(k.GrowTree), Description: "Move the divider towards the preview"},
+				{Key: bindingKeys(k.GoToLine), Description: "Scroll the focused text preview to a line"},
+				{Key: bindingKeys(k.ToggleSummary), Description: "Show or hide the directory summary"},
+				{Key: bindingKeys(k.ToggleSizes), Description: "Toggle exact sizes in bytes"},
+				{Key: ".", Description: "Toggle hidden files"},
+				{Key: bindingKeys(k.CycleTheme), Description: "Switch to the next theme"},
+				{Key: bindingKeys(k.Help), Description: "Show or hide this help"},
+				{Key: bindingKeys(k.ReloadConfig), Description: "Reload the config"},
+				{Key: bindingKeys(k.Submit), Description: "Confirm the prompt or dialog"},
+				{Key: bindingKeys(k.Cancel), Description: "Cancel the prompt, dialog, copy or marked item"},
+				{Key: bindingKeys(k.Exit), Description: "Exit FM"},
+				{Key: bindingKeys(k.Quit), Description: "Exit FM, even while filtering"},
+			},
+		},
+	}
+}
+
+// newHelp creates the help shown in the right box while nothing is previewed.
+func newHelp(cfg config.Config, theme theme.Theme, keys KeyMap) help.Bubble {
+	return help.New(
+		false,
+		cfg.Settings.Borderless,
+		"Help",
+		help.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+		theme.InactiveBoxBorderColor,
+		keys.helpEntries(),
+	)
+}
+
+// helpEntries returns the keybindings of all groups as entries of the help
+// shown in the right box.
+func (k KeyMap) helpEntries() []help.Entry {
+	var entries []help.Entry
+	for _, group := range k.helpGroups() {
+		for _, entry := range group.Entries {
+			entries = append(entries, help.Entry{Key: entry.Key, Description: entry.Description})
+		}
+	}
+
+	return entries
+}
+
+// handleHelpKey scrolls the help overlay, closing it on the help, cancel or
+// exit keys.
+func (b *Bubble) handleHelpKey(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, b.keys.Help), key.Matches(msg, b.keys.Cancel), key.Matches(msg, b.keys.Exit):
+		b.keyHelp.Hide()
+	default:
+		b.keyHelp, cmd = b.keyHelp.Update(msg)
+	}
+
+	return cmd
+}
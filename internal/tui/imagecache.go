@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"image"
+	"os"
+	"time"
+)
+
+const (
+	// cachedImageSide is the length of the longest side decoded images are
+	// downscaled to before they are cached, which is plenty for a preview
+	// drawn with two pixels per cell.
+	cachedImageSide = 512
+
+	// maxCachedImages is the number of decoded images kept in the cache.
+	maxCachedImages = 16
+)
+
+// imageKey identifies a version of an image file, so that the cached image
+// is decoded again once the file changes.
+type imageKey struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// newImageKey returns the key of the current version of an image file.
+func newImageKey(path string) (imageKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return imageKey{}, err
+	}
+
+	return imageKey{path: path, size: info.Size(), modTime: info.ModTime()}, nil
+}
+
+// imageCache keeps the most recently decoded images, evicting the oldest
+// once it is full.
+type imageCache struct {
+	images map[imageKey]image.Image
+	keys   []imageKey
+}
+
+// get returns the cached image with the given key.
+func (c imageCache) get(key imageKey) (image.Image, bool) {
+	img, ok := c.images[key]
+
+	return img, ok
+}
+
+// add caches an image under the given key.
+func (c *imageCache) add(key imageKey, img image.Image) {
+	if c.images == nil {
+		c.images = make(map[imageKey]image.Image)
+	}
+
+	if _, ok := c.images[key]; ok {
+		return
+	}
+
+	if len(c.keys) == maxCachedImages {
+		delete(c.images, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+
+	c.images[key] = img
+	c.keys = append(c.keys, key)
+}
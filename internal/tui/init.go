@@ -6,5 +6,18 @@ import (
 
 // Init intializes the UI.
 func (b Bubble) Init() tea.Cmd {
-	return b.filetree.Init()
+	cmds := []tea.Cmd{b.filetree.Init()}
+	if b.configWatch.watcher != nil {
+		cmds = append(cmds, waitForConfigChangeCmd(b.configWatch.watcher, b.configWatch.path))
+	}
+
+	if b.segment.clockTicking {
+		cmds = append(cmds, clockTickCmd())
+	}
+
+	if b.status.message != "" {
+		cmds = append(cmds, clearStatusMessageAfter(b.status.messageID))
+	}
+
+	return tea.Batch(cmds...)
 }
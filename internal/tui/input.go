@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type inputMode int
+
+const (
+	noInputMode inputMode = iota
+	bookmarkNameInputMode
+	createFileInputMode
+	createDirectoryInputMode
+	goToPathInputMode
+	renameInputMode
+	moveInputMode
+	saveAsInputMode
+	bulkRenameInputMode
+	templateInputMode
+	clipboardInputMode
+	goToLineInputMode
+)
+
+// promptState represents the input in the status bar and what its value is
+// used for.
+type promptState struct {
+	input           textinput.Model
+	mode            inputMode
+	source          string
+	renameCollision bool
+	clipboardText   string
+	bulkRenamePaths []string
+	movePaths       []string
+	completions     []string
+	completionIndex int
+}
+
+// startInput focuses the input in the status bar.
+func (b *Bubble) startInput(mode inputMode, prompt, value string) {
+	b.prompt.mode = mode
+	b.prompt.input.Prompt = prompt
+	b.prompt.input.SetValue(value)
+	b.prompt.input.CursorEnd()
+	b.prompt.input.Focus()
+}
+
+// stopInput blurs the input in the status bar.
+func (b *Bubble) stopInput() {
+	b.prompt.mode = noInputMode
+	b.prompt.completions = nil
+	b.prompt.renameCollision = false
+	b.prompt.input.PromptStyle = lipgloss.NewStyle()
+	b.prompt.input.Blur()
+	b.prompt.input.SetValue("")
+}
+
+// completePath completes the directory typed into the input. Once there is
+// nothing left to complete, repeated presses cycle through the directories
+// it matches.
+func (b *Bubble) completePath() {
+	value := b.prompt.input.Value()
+
+	if len(b.prompt.completions) > 0 && value == b.prompt.completions[b.prompt.completionIndex] {
+		b.prompt.completionIndex = (b.prompt.completionIndex + 1) % len(b.prompt.completions)
+		b.prompt.input.SetValue(b.prompt.completions[b.prompt.completionIndex])
+		b.prompt.input.CursorEnd()
+
+		return
+	}
+
+	b.prompt.completions = nil
+	if completed := filesystem.CompleteDirectory(value); completed != value {
+		value = completed
+	} else if completions := filesystem.DirectoryCompletions(value); len(completions) > 0 {
+		b.prompt.completions = completions
+		b.prompt.completionIndex = 0
+		value = completions[0]
+	}
+
+	b.prompt.input.SetValue(value)
+	b.prompt.input.CursorEnd()
+}
+
+// handleInputKey handles keys while the input in the status bar is focused.
+func (b *Bubble) handleInputKey(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, b.keys.Cancel):
+		b.stopInput()
+	case key.Matches(msg, b.keys.Submit):
+		mode, value := b.prompt.mode, b.prompt.input.Value()
+		b.stopInput()
+
+		switch mode {
+		case bookmarkNameInputMode:
+			cmd = addBookmarkCmd(value, b.currentDirectory())
+		case createFileInputMode:
+			cmd = createItemCmd(b.currentDirectory(), value, false)
+		case createDirectoryInputMode:
+			cmd = createItemCmd(b.currentDirectory(), value, true)
+		case goToPathInputMode:
+			cmd = b.goToPath(value)
+		case renameInputMode:
+			cmd = renameItemCmd(b.prompt.source, value)
+		case moveInputMode:
+			cmd = b.moveItemsTo(b.prompt.movePaths, value)
+		case saveAsInputMode:
+			cmd = b.saveAs(b.prompt.source, value)
+		case bulkRenameInputMode:
+			cmd = b.confirmBulkRename(value)
+		case templateInputMode:
+			cmd = createFromTemplateCmd(b.currentDirectory(), value, b.prompt.source)
+		case clipboardInputMode:
+			cmd = createFromClipboardCmd(b.currentDirectory(), value, b.prompt.clipboardText)
+		case goToLineInputMode:
+			cmd = b.goToLine(value)
+		case noInputMode:
+		}
+	case (b.prompt.mode == goToPathInputMode || b.prompt.mode == moveInputMode) && msg.Type == tea.KeyTab:
+		b.completePath()
+	default:
+		b.prompt.completions = nil
+		b.prompt.input, cmd = b.prompt.input.Update(msg)
+		b.checkRenameCollision()
+	}
+
+	return cmd
+}
+
+// checkRenameCollision checks whether the name typed while renaming is
+// taken by another item in the directory, coloring the prompt if it is.
+func (b *Bubble) checkRenameCollision() {
+	if b.prompt.mode != renameInputMode {
+		return
+	}
+
+	name := b.prompt.input.Value()
+	target := filepath.Join(filepath.Dir(b.prompt.source), name)
+
+	b.prompt.renameCollision = false
+	if name != "" && target != filepath.Clean(b.prompt.source) {
+		if _, err := os.Lstat(target); err == nil {
+			b.prompt.renameCollision = true
+		}
+	}
+
+	b.prompt.input.PromptStyle = lipgloss.NewStyle()
+	if b.prompt.renameCollision {
+		b.prompt.input.PromptStyle = b.prompt.input.PromptStyle.Bold(true).Foreground(b.theme.WarningColor)
+	}
+}
+
+// startRename focuses the input with the name of the selected item, placing
+// the cursor in front of the extension of a file.
+func (b *Bubble) startRename() {
+	selectedFile := b.filetree.GetSelectedItem()
+	if selectedFile.FileName() == "" {
+		return
+	}
+
+	name := filepath.Base(selectedFile.FileName())
+	b.prompt.source = selectedFile.FileName()
+	b.startInput(renameInputMode, "Rename: ", name)
+	b.checkRenameCollision()
+
+	if !selectedFile.IsDirectory() {
+		b.placeCursorBeforeExtension(name)
+	}
+}
+
+// renameItemCmd renames an item within its directory.
+func renameItemCmd(path, name string) tea.Cmd {
+	return func() tea.Msg {
+		if name == "" {
+			return statusMessageMsg("A name is required")
+		}
+
+		newPath := filepath.Join(filepath.Dir(path), name)
+		if err := filesystem.Rename(path, newPath); err != nil {
+			return statusMessageMsg(err.Error())
+		}
+
+		return fileOperationMsg{
+			message:    fmt.Sprintf("Renamed %s to %s", filepath.Base(path), name),
+			selectPath: newPath,
+			undo:       renameUndo(path, newPath),
+		}
+	}
+}
+
+// startSaveAs focuses the input with the name of the selected file to pick
+// the name a copy of it is saved under, placing the cursor in front of the
+// extension.
+func (b *Bubble) startSaveAs() {
+	selectedFile := b.filetree.GetSelectedItem()
+	if selectedFile.FileName() == "" || selectedFile.IsDirectory() {
+		return
+	}
+
+	name := filepath.Base(selectedFile.FileName())
+	b.prompt.source = selectedFile.FileName()
+	b.startInput(saveAsInputMode, "Save as: ", name)
+	b.placeCursorBeforeExtension(name)
+}
+
+// startMove focuses the input with the current directory to pick the
+// directory the selected items, or the item under the cursor if nothing is
+// selected, are moved to.
+func (b *Bubble) startMove() {
+	paths := b.selectedPaths()
+	if len(paths) == 0 {
+		return
+	}
+
+	prompt := "Move to: "
+	if len(paths) > 1 {
+		prompt = fmt.Sprintf("Move %d items to: ", len(paths))
+	}
+
+	dir := strings.TrimSuffix(b.currentDirectory(), string(filepath.Separator))
+	b.prompt.movePaths = paths
+	b.startInput(moveInputMode, prompt, dir+string(filepath.Separator))
+}
+
+// startGoToLine focuses the input to pick the line the focused preview
+// scrolls to, which only text previews support.
+func (b *Bubble) startGoToLine() tea.Cmd {
+	if b.state != showTextState {
+		return b.setStatusMessage("This preview can't jump to a line")
+	}
+
+	b.startInput(goToLineInputMode, ":", "")
+
+	return nil
+}
+
+// goToLine scrolls the text preview to the typed line number.
+func (b *Bubble) goToLine(value string) tea.Cmd {
+	line, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return b.setStatusMessage(fmt.Sprintf("%q is not a line number", value))
+	}
+
+	b.renderer.ScrollToLine(line)
+
+	return nil
+}
+
+// placeCursorBeforeExtension moves the cursor of the input in front of the
+// extension of a file name.
+func (b *Bubble) placeCursorBeforeExtension(name string) {
+	if ext := filepath.Ext(name); ext != name {
+		b.prompt.input.SetCursor(len([]rune(name)) - len([]rune(ext)))
+	}
+}
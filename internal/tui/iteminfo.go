@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/gitstatus"
+	"github.com/knipferrc/fm/internal/logger"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// itemInfo represents the size and symlink target of the selected item shown
+// in the status bar. The size is unknown if part of the item couldn't be read.
+type itemInfo struct {
+	path        string
+	size        int64
+	sizeUnknown bool
+	linkTarget  string
+	linkDir     string
+	cancelSize  context.CancelFunc
+}
+
+// dirInfo holds what is known about the directories listed, along with the
+// directory each value was last asked for.
+type dirInfo struct {
+	summaries       map[string]filesystem.Summary
+	summaryPath     string
+	gitStatuses     map[string]gitstatus.Status
+	gitStatusPath   string
+	hiddenCounts    map[string]int
+	hiddenCountPath string
+	readErrors      map[string]error
+	diskSpace       *filesystem.DiskSpace
+	diskSpaceDir    string
+}
+
+// newDirInfo returns a dirInfo without anything known yet.
+func newDirInfo() dirInfo {
+	return dirInfo{
+		summaries:    make(map[string]filesystem.Summary),
+		gitStatuses:  make(map[string]gitstatus.Status),
+		hiddenCounts: make(map[string]int),
+		readErrors:   make(map[string]error),
+	}
+}
+
+// forget drops what is known about the entries of dir.
+func (d *dirInfo) forget(dir string) {
+	delete(d.gitStatuses, dir)
+	delete(d.hiddenCounts, dir)
+	delete(d.readErrors, dir)
+}
+
+// forgetEntries drops what is known about the entries of every directory,
+// along with the disk space, as files may have changed anywhere.
+func (d *dirInfo) forgetEntries() {
+	d.gitStatuses = make(map[string]gitstatus.Status)
+	d.hiddenCounts = make(map[string]int)
+	d.readErrors = make(map[string]error)
+	d.diskSpaceDir = ""
+}
+
+type dirSizeMsg struct {
+	path string
+	size int64
+	err  error
+}
+
+type symlinkMsg struct {
+	path     string
+	target   string
+	resolved string
+	isDir    bool
+}
+
+type dirSummaryMsg struct {
+	path    string
+	summary filesystem.Summary
+}
+
+type gitStatusMsg struct {
+	dir    string
+	status gitstatus.Status
+}
+
+type hiddenCountMsg struct {
+	dir   string
+	count int
+	err   error
+}
+
+type diskSpaceMsg struct {
+	dir   string
+	space filesystem.DiskSpace
+	err   error
+}
+
+// updateItemSize starts calculating the size of the selected item if the
+// selection changed, cancelling any calculation still running for the old one.
+func (b *Bubble) updateItemSize() tea.Cmd {
+	selectedFile := b.filetree.GetSelectedItem()
+	if selectedFile.FileName() == b.item.path {
+		return nil
+	}
+
+	if b.item.cancelSize != nil {
+		b.item.cancelSize()
+		b.item.cancelSize = nil
+	}
+
+	b.item.size = -1
+	b.item.sizeUnknown = false
+	b.item.linkTarget = ""
+	b.item.linkDir = ""
+	b.item.path = selectedFile.FileName()
+	if b.item.path == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.item.cancelSize = cancel
+
+	return tea.Batch(readSymlinkCmd(b.item.path), calculateDirectorySize(ctx, b.item.path))
+}
+
+// selectedLinkedDir returns the directory the symlink under the cursor of
+// the filetree resolves to, or an empty string if it isn't a symlink to a
+// directory or hasn't been resolved yet.
+func (b Bubble) selectedLinkedDir() string {
+	if b.activeBox != 0 || b.filetree.GetSelectedItem().FileName() != b.item.path {
+		return ""
+	}
+
+	return b.item.linkDir
+}
+
+// updateDirSummary starts summarizing the current directory if the summary
+// is shown and the directory hasn't been summarized yet.
+func (b *Bubble) updateDirSummary() tea.Cmd {
+	if !b.showDirSummary || b.currentDir == "" || b.dirs.summaryPath == b.currentDir {
+		return nil
+	}
+
+	if _, ok := b.dirs.summaries[b.currentDir]; ok {
+		return nil
+	}
+
+	b.dirs.summaryPath = b.currentDir
+
+	return summarizeDirectoryCmd(b.currentDir)
+}
+
+// updateGitStatus starts reading the git status of the current directory if
+// it is enabled and hasn't been read yet.
+func (b *Bubble) updateGitStatus() tea.Cmd {
+	if !b.config.Settings.ShowGitStatus || b.currentDir == "" || b.dirs.gitStatusPath == b.currentDir {
+		return nil
+	}
+
+	if _, ok := b.dirs.gitStatuses[b.currentDir]; ok {
+		return nil
+	}
+
+	b.dirs.gitStatusPath = b.currentDir
+
+	return readGitStatusCmd(b.currentDir)
+}
+
+// updateHiddenCount starts counting the hidden entries of the current
+// directory if they haven't been counted yet.
+func (b *Bubble) updateHiddenCount() tea.Cmd {
+	if b.currentDir == "" || b.dirs.hiddenCountPath == b.currentDir {
+		return nil
+	}
+
+	if _, ok := b.dirs.hiddenCounts[b.currentDir]; ok {
+		return nil
+	}
+
+	b.dirs.hiddenCountPath = b.currentDir
+
+	return countHiddenCmd(b.currentDir)
+}
+
+// updateDiskSpace starts reading the disk space of the filesystem containing
+// the current directory once the directory changes.
+func (b *Bubble) updateDiskSpace() tea.Cmd {
+	if !b.config.Settings.ShowDiskSpace || b.currentDir == "" || b.dirs.diskSpaceDir == b.currentDir {
+		return nil
+	}
+
+	b.dirs.diskSpaceDir = b.currentDir
+
+	return readDiskSpaceCmd(b.currentDir)
+}
+
+// readSymlinkCmd reads the target of the symlink at path in the background,
+// as resolving it can block on an unresponsive network mount. Nothing is sent
+// if the path isn't a symlink.
+func readSymlinkCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		target, resolved, ok := filesystem.SymlinkTarget(path)
+		if !ok {
+			return nil
+		}
+
+		msg := symlinkMsg{path: path, target: target, resolved: resolved}
+		if resolved != "" {
+			if info, err := os.Stat(resolved); err == nil {
+				msg.isDir = info.IsDir()
+			}
+		}
+
+		return msg
+	}
+}
+
+// calculateDirectorySize sums the size of all regular files at or below the
+// given path without following symlinks. The walk stops early once ctx is
+// cancelled, which happens when the selection changes before it finishes.
+func calculateDirectorySize(ctx context.Context, path string) tea.Cmd {
+	return func() tea.Msg {
+		size, err := filesystem.Size(ctx, path)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+
+		if err != nil {
+			logger.Error("calculating the size failed", "path", path, "err", err)
+		}
+
+		return dirSizeMsg{path: path, size: size, err: err}
+	}
+}
+
+// summarizeDirectoryCmd counts the entries of a directory and adds up their size.
+func summarizeDirectoryCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		summary, err := filesystem.Summarize(context.Background(), path)
+		if err != nil {
+			logger.Error("summarizing the directory failed", "dir", path, "err", err)
+		}
+
+		return dirSummaryMsg{path: path, summary: summary}
+	}
+}
+
+// readGitStatusCmd reads the git status of the entries of a directory,
+// which is empty outside of a git working tree.
+func readGitStatusCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		status, err := gitstatus.Read(dir)
+		if err != nil && !errors.Is(err, gitstatus.ErrNotRepository) {
+			logger.Error("reading the git status failed", "dir", dir, "err", err)
+		}
+
+		return gitStatusMsg{dir: dir, status: status}
+	}
+}
+
+// countHiddenCmd counts the hidden entries of a directory.
+func countHiddenCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		count, err := filesystem.CountHidden(dir)
+		if err != nil {
+			logger.Error("counting hidden files failed", "dir", dir, "err", err)
+		}
+
+		return hiddenCountMsg{dir: dir, count: count, err: err}
+	}
+}
+
+// readDiskSpaceCmd reads the disk space of the filesystem containing a directory.
+func readDiskSpaceCmd(dir string) tea.Cmd {
+	return func() tea.Msg {
+		space, err := filesystem.GetDiskSpace(dir)
+
+		return diskSpaceMsg{dir: dir, space: space, err: err}
+	}
+}
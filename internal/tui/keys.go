@@ -1,14 +1,64 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines the keybindings for the app.
 type KeyMap struct {
-	Quit         key.Binding
-	Exit         key.Binding
-	ToggleBox    key.Binding
-	OpenFile     key.Binding
-	ReloadConfig key.Binding
+	Quit            key.Binding
+	Exit            key.Binding
+	ToggleBox       key.Binding
+	OpenFile        key.Binding
+	ReloadConfig    key.Binding
+	CopyPath        key.Binding
+	CopyContents    key.Binding
+	Delete          key.Binding
+	Select          key.Binding
+	Submit          key.Binding
+	Cancel          key.Binding
+	AddBookmark     key.Binding
+	ShowBookmarks   key.Binding
+	ShowMounts      key.Binding
+	NewTab          key.Binding
+	CloseTab        key.Binding
+	NextTab         key.Binding
+	PreviousTab     key.Binding
+	Edit            key.Binding
+	OpenShell       key.Binding
+	CreateFile      key.Binding
+	NewFromTemplate key.Binding
+	FromClipboard   key.Binding
+	GoToLine        key.Binding
+	CreateDirectory key.Binding
+	Copy            key.Binding
+	GoToPath        key.Binding
+	TogglePreview   key.Binding
+	ToggleSummary   key.Binding
+	OpenWith        key.Binding
+	HalfPageDown    key.Binding
+	HalfPageUp      key.Binding
+	PageDown        key.Binding
+	PageUp          key.Binding
+	Rename          key.Binding
+	Move            key.Binding
+	SaveAs          key.Binding
+	BulkRename      key.Binding
+	ToggleSizes     key.Binding
+	ParentDirectory key.Binding
+	Help            key.Binding
+	Undo            key.Binding
+	Cut             key.Binding
+	Paste           key.Binding
+	GoToStartDir    key.Binding
+	Duplicate       key.Binding
+	CycleTheme      key.Binding
+	Find            key.Binding
+	ShrinkTree      key.Binding
+	GrowTree        key.Binding
 }
 
 // DefaultKeyMap returns a set of default keybindings.
@@ -29,5 +79,337 @@ func DefaultKeyMap() KeyMap {
 		ReloadConfig: key.NewBinding(
 			key.WithKeys("ctrl+r"),
 		),
+		CopyPath: key.NewBinding(
+			key.WithKeys("y"),
+		),
+		CopyContents: key.NewBinding(
+			key.WithKeys("Y"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("x"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("v"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+		),
+		AddBookmark: key.NewBinding(
+			key.WithKeys("B"),
+		),
+		ShowBookmarks: key.NewBinding(
+			key.WithKeys("'"),
+		),
+		ShowMounts: key.NewBinding(
+			key.WithKeys("V"),
+		),
+		NewTab: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+		),
+		CloseTab: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("]"),
+		),
+		PreviousTab: key.NewBinding(
+			key.WithKeys("["),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+		),
+		OpenShell: key.NewBinding(
+			key.WithKeys("!"),
+		),
+		CreateFile: key.NewBinding(
+			key.WithKeys("n"),
+		),
+		NewFromTemplate: key.NewBinding(
+			key.WithKeys("t"),
+		),
+		FromClipboard: key.NewBinding(
+			key.WithKeys("ctrl+v"),
+		),
+		GoToLine: key.NewBinding(
+			key.WithKeys(":"),
+		),
+		CreateDirectory: key.NewBinding(
+			key.WithKeys("N"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+		),
+		GoToPath: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+		),
+		TogglePreview: key.NewBinding(
+			key.WithKeys("P"),
+		),
+		ToggleSummary: key.NewBinding(
+			key.WithKeys("S"),
+		),
+		OpenWith: key.NewBinding(
+			key.WithKeys("o"),
+		),
+		HalfPageDown: key.NewBinding(
+			key.WithKeys("ctrl+d"),
+		),
+		HalfPageUp: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("ctrl+b"),
+		),
+		Rename: key.NewBinding(
+			key.WithKeys("r"),
+		),
+		Move: key.NewBinding(
+			key.WithKeys("m"),
+		),
+		SaveAs: key.NewBinding(
+			key.WithKeys("A"),
+		),
+		BulkRename: key.NewBinding(
+			key.WithKeys("M"),
+		),
+		ToggleSizes: key.NewBinding(
+			key.WithKeys("#"),
+		),
+		ParentDirectory: key.NewBinding(
+			key.WithKeys("backspace"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("U"),
+		),
+		Cut: key.NewBinding(
+			key.WithKeys("d"),
+		),
+		Paste: key.NewBinding(
+			key.WithKeys("p"),
+		),
+		GoToStartDir: key.NewBinding(
+			key.WithKeys("H"),
+		),
+		Duplicate: key.NewBinding(
+			key.WithKeys("D"),
+		),
+		CycleTheme: key.NewBinding(
+			key.WithKeys("T"),
+		),
+		Find: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+		),
+		ShrinkTree: key.NewBinding(
+			key.WithKeys("<"),
+		),
+		GrowTree: key.NewBinding(
+			key.WithKeys(">"),
+		),
+	}
+}
+
+// actions maps the action names used in the config file to their keybinding.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":             &k.Quit,
+		"exit":             &k.Exit,
+		"toggle_box":       &k.ToggleBox,
+		"open_file":        &k.OpenFile,
+		"reload_config":    &k.ReloadConfig,
+		"copy_path":        &k.CopyPath,
+		"copy_contents":    &k.CopyContents,
+		"delete":           &k.Delete,
+		"select":           &k.Select,
+		"submit":           &k.Submit,
+		"cancel":           &k.Cancel,
+		"add_bookmark":     &k.AddBookmark,
+		"show_bookmarks":   &k.ShowBookmarks,
+		"show_mounts":      &k.ShowMounts,
+		"new_tab":          &k.NewTab,
+		"close_tab":        &k.CloseTab,
+		"next_tab":         &k.NextTab,
+		"previous_tab":     &k.PreviousTab,
+		"edit":             &k.Edit,
+		"open_shell":       &k.OpenShell,
+		"create_file":      &k.CreateFile,
+		"from_template":    &k.NewFromTemplate,
+		"from_clipboard":   &k.FromClipboard,
+		"go_to_line":       &k.GoToLine,
+		"create_directory": &k.CreateDirectory,
+		"copy":             &k.Copy,
+		"go_to_path":       &k.GoToPath,
+		"toggle_preview":   &k.TogglePreview,
+		"toggle_summary":   &k.ToggleSummary,
+		"open_with":        &k.OpenWith,
+		"half_page_down":   &k.HalfPageDown,
+		"half_page_up":     &k.HalfPageUp,
+		"page_down":        &k.PageDown,
+		"page_up":          &k.PageUp,
+		"rename":           &k.Rename,
+		"move":             &k.Move,
+		"save_as":          &k.SaveAs,
+		"bulk_rename":      &k.BulkRename,
+		"toggle_sizes":     &k.ToggleSizes,
+		"parent_directory": &k.ParentDirectory,
+		"help":             &k.Help,
+		"undo":             &k.Undo,
+		"cut":              &k.Cut,
+		"paste":            &k.Paste,
+		"go_to_start_dir":  &k.GoToStartDir,
+		"duplicate":        &k.Duplicate,
+		"cycle_theme":      &k.CycleTheme,
+		"find_file":        &k.Find,
+		"shrink_tree":      &k.ShrinkTree,
+		"grow_tree":        &k.GrowTree,
+	}
+}
+
+// filetreeKeys maps the keys handled by the teacup filetree, which can't be
+// bound to actions, to what they do. The digits are reserved as well, since
+// they count the motion which follows them.
+var filetreeKeys = map[string]string{
+	"j":     "move down",
+	"down":  "move down",
+	"k":     "move up",
+	"up":    "move up",
+	"h":     "paginate left",
+	"left":  "paginate left",
+	"l":     "paginate right",
+	"right": "paginate right",
+	"g":     "jump to the top",
+	"G":     "jump to the bottom",
+	"~":     "go to the home directory",
+	"R":     "go to the root directory",
+	".":     "toggle hidden files",
+	"/":     "filter the current directory",
+	"z":     "zip the selected item",
+	"u":     "unzip the selected item",
+}
+
+// filetreeActionKeys are the keys the teacup filetree acts on the selected
+// item with, deleting, moving, creating, copying, renaming, editing and
+// copying its path. fm does all of these itself, so the keys never reach the
+// filetree, even once their action is bound to another key.
+var filetreeActionKeys = []string{"x", "m", "n", "N", "c", "r", "e", "y"}
+
+// reservedKey returns the first of the keys which can't be bound to an
+// action, along with what it does instead.
+func reservedKey(keys []string) (string, string, bool) {
+	for _, k := range keys {
+		if what, ok := filetreeKeys[k]; ok {
+			return k, what, true
+		}
+
+		if len(k) == 1 && k[0] >= '0' && k[0] <= '9' {
+			return k, "count motions", true
+		}
+	}
+
+	return "", "", false
+}
+
+// conflictingAction returns the name of another action already using one of the keys.
+func conflictingAction(actions map[string]*key.Binding, name string, keys []string) (string, bool) {
+	for _, otherName := range actionNames(actions) {
+		if otherName == name {
+			continue
+		}
+
+		for _, otherKey := range actions[otherName].Keys() {
+			for _, k := range keys {
+				if k == otherKey {
+					return otherName, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// actionNames returns the names of the actions in alphabetical order.
+func actionNames(actions map[string]*key.Binding) []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
 	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NewKeyMap returns the default keybindings with the bindings from the config
+// applied. Invalid or conflicting bindings fall back to their defaults and are
+// reported as warnings. Bindings are checked for conflicts once all of them
+// are applied, so that two actions can swap their keys.
+func NewKeyMap(bindings map[string][]string) (KeyMap, []string) {
+	keyMap := DefaultKeyMap()
+	actions := keyMap.actions()
+
+	var (
+		warnings []string
+		names    []string
+	)
+
+	for name := range bindings {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	defaults := make(map[string][]string)
+	for _, name := range names {
+		keys := bindings[name]
+
+		binding, ok := actions[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown keybinding action %q", name))
+			continue
+		}
+
+		if len(keys) == 0 {
+			warnings = append(warnings, fmt.Sprintf("no keys set for %q, using the default", name))
+			continue
+		}
+
+		if k, what, ok := reservedKey(keys); ok {
+			warnings = append(warnings, fmt.Sprintf("key %q of %q is used to %s, using the default", k, name, what))
+			continue
+		}
+
+		defaults[name] = binding.Keys()
+		binding.SetKeys(keys...)
+	}
+
+	// Falling back to the default of one binding can make it conflict with
+	// another changed binding, so conflicts are checked until none are left.
+	for conflicts := true; conflicts; {
+		conflicts = false
+
+		for _, name := range names {
+			keys, changed := defaults[name]
+			if !changed {
+				continue
+			}
+
+			if otherName, ok := conflictingAction(actions, name, actions[name].Keys()); ok {
+				warnings = append(warnings, fmt.Sprintf("keys for %q conflict with %q, using the default", name, otherName))
+				actions[name].SetKeys(keys...)
+				delete(defaults, name)
+				conflicts = true
+			}
+		}
+	}
+
+	return keyMap, warnings
 }
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]string
+		want     map[string][]string
+		warnings []string
+	}{
+		{
+			name:     "rebind",
+			bindings: map[string][]string{"delete": {"delete", "X"}},
+			want:     map[string][]string{"delete": {"delete", "X"}},
+		},
+		{
+			name:     "swap keys",
+			bindings: map[string][]string{"copy_path": {"Y"}, "copy_contents": {"y"}},
+			want:     map[string][]string{"copy_path": {"Y"}, "copy_contents": {"y"}},
+		},
+		{
+			name:     "conflict with a default",
+			bindings: map[string][]string{"delete": {"y"}},
+			want:     map[string][]string{"delete": {"x"}, "copy_path": {"y"}},
+			warnings: []string{`keys for "delete" conflict with "copy_path", using the default`},
+		},
+		{
+			name:     "conflict with a binding falling back",
+			bindings: map[string][]string{"copy_path": {"Y"}, "delete": {"y"}},
+			want:     map[string][]string{"copy_path": {"y"}, "copy_contents": {"Y"}, "delete": {"x"}},
+			warnings: []string{
+				`keys for "copy_path" conflict with "copy_contents", using the default`,
+				`keys for "delete" conflict with "copy_path", using the default`,
+			},
+		},
+		{
+			name:     "filetree key",
+			bindings: map[string][]string{"delete": {"j"}},
+			want:     map[string][]string{"delete": {"x"}},
+			warnings: []string{`key "j" of "delete" is used to move down, using the default`},
+		},
+		{
+			name:     "count digit",
+			bindings: map[string][]string{"delete": {"5"}},
+			want:     map[string][]string{"delete": {"x"}},
+			warnings: []string{`key "5" of "delete" is used to count motions, using the default`},
+		},
+		{
+			name:     "no keys",
+			bindings: map[string][]string{"delete": {}},
+			want:     map[string][]string{"delete": {"x"}},
+			warnings: []string{`no keys set for "delete", using the default`},
+		},
+		{
+			name:     "unknown action",
+			bindings: map[string][]string{"explode": {"e"}},
+			warnings: []string{`unknown keybinding action "explode"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyMap, warnings := NewKeyMap(tt.bindings)
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("NewKeyMap warned %q, want %q", warnings, tt.warnings)
+			}
+
+			actions := keyMap.actions()
+			for name, want := range tt.want {
+				if got := actions[name].Keys(); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s is bound to %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOwnsFiletreeActionKeys(t *testing.T) {
+	keyMap, _ := NewKeyMap(map[string][]string{"delete": {"delete", "X"}})
+	b := Bubble{keys: keyMap}
+
+	for _, k := range filetreeActionKeys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		if !b.ownsKey(msg) {
+			t.Errorf("key %q reaches the filetree", k)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/knipferrc/teacup/statusbar"
+)
+
+const (
+	// verticalLayout is the layout which places the preview below the filetree.
+	verticalLayout = "vertical"
+
+	// filetreeRowHeight is the number of lines an item takes up in the filetree.
+	filetreeRowHeight = 3
+
+	// filetreeChromeHeight is the number of lines the filetree uses for
+	// everything besides its items and its borders.
+	filetreeChromeHeight = 8
+
+	// minSplitRatio and maxSplitRatio bound the percentage of the width, or
+	// the height with the vertical layout, taken up by the filetree.
+	minSplitRatio = 20
+	maxSplitRatio = 80
+
+	// splitRatioStep is the percentage the divider moves per key press.
+	splitRatioStep = 5
+)
+
+// deactivateALlBubbles sets all bubbles to inactive.
+func (b *Bubble) deactivateAllBubbles() {
+	b.filetree.SetIsActive(false)
+	b.code.SetIsActive(false)
+	b.image.SetIsActive(false)
+	b.help.SetIsActive(false)
+	b.renderer.SetIsActive(false)
+}
+
+// resetBorderColors resets all bubble border colors to default.
+func (b *Bubble) resetBorderColors() {
+	b.filetree.SetBorderColor(b.theme.InactiveBoxBorderColor)
+	b.help.SetBorderColor(b.theme.InactiveBoxBorderColor)
+	b.code.SetBorderColor(b.theme.InactiveBoxBorderColor)
+	b.image.SetBorderColor(b.theme.InactiveBoxBorderColor)
+	b.renderer.SetBorderColor(b.theme.InactiveBoxBorderColor)
+}
+
+// updateActiveBox activates the bubble shown in the active box and highlights its border.
+func (b *Bubble) updateActiveBox() {
+	b.deactivateAllBubbles()
+	b.resetBorderColors()
+
+	if b.activeBox == 0 {
+		b.filetree.SetIsActive(true)
+		b.filetree.SetBorderColor(b.theme.ActiveBoxBorderColor)
+
+		return
+	}
+
+	switch b.state {
+	case idleState:
+		b.help.SetIsActive(true)
+		b.help.SetBorderColor(b.theme.ActiveBoxBorderColor)
+	case showCodeState:
+		b.code.SetIsActive(true)
+		b.code.SetBorderColor(b.theme.ActiveBoxBorderColor)
+	case showImageState:
+		b.image.SetIsActive(true)
+		b.image.SetBorderColor(b.theme.ActiveBoxBorderColor)
+	case showTextState:
+		b.renderer.SetIsActive(true)
+		b.renderer.SetBorderColor(b.theme.ActiveBoxBorderColor)
+	case showPickerState:
+		b.picker.SetIsActive(true)
+		b.picker.SetBorderColor(b.theme.ActiveBoxBorderColor)
+	}
+}
+
+// toggleBox toggles between the two boxes.
+func (b *Bubble) toggleBox() {
+	if !b.showPreview {
+		return
+	}
+
+	b.activeBox = (b.activeBox + 1) % 2
+	b.updateActiveBox()
+}
+
+// setPreviewVisible shows or hides the right box, letting the filetree
+// fill the screen while it is hidden.
+func (b *Bubble) setPreviewVisible(visible bool) []tea.Cmd {
+	if b.showPreview == visible {
+		return nil
+	}
+
+	b.showPreview = visible
+	if !visible && b.activeBox == 1 {
+		b.activeBox = 0
+		b.updateActiveBox()
+	}
+
+	return b.resize()
+}
+
+// panesHeight returns the height available to the panes.
+func (b Bubble) panesHeight() int {
+	height := b.height - statusbar.Height
+	if len(b.tabs.list) > 1 {
+		height -= tabBarHeight
+	}
+
+	return height
+}
+
+// paneSizes returns the sizes of the filetree and the preview, placing them
+// side by side or, with the vertical layout, on top of each other.
+func (b Bubble) paneSizes() (treeWidth, treeHeight, previewWidth, previewHeight int) {
+	height := b.panesHeight()
+
+	switch {
+	case !b.showPreview:
+		return b.width, height, b.width - b.width*b.splitRatio/100, height
+	case b.config.Settings.Layout == verticalLayout:
+		treeHeight := height * b.splitRatio / 100
+		return b.width, treeHeight, b.width, height - treeHeight
+	default:
+		treeWidth := b.width * b.splitRatio / 100
+		return treeWidth, height, b.width - treeWidth, height
+	}
+}
+
+// clampSplitRatio keeps a split ratio within the allowed bounds.
+func clampSplitRatio(ratio int) int {
+	switch {
+	case ratio < minSplitRatio:
+		return minSplitRatio
+	case ratio > maxSplitRatio:
+		return maxSplitRatio
+	default:
+		return ratio
+	}
+}
+
+// moveDivider grows the filetree by delta percent of the terminal, shrinking
+// the preview by as much, and resizes both.
+func (b *Bubble) moveDivider(delta int) []tea.Cmd {
+	ratio := clampSplitRatio(b.splitRatio + delta)
+	if ratio == b.splitRatio || !b.showPreview {
+		return nil
+	}
+
+	b.splitRatio = ratio
+
+	return b.resize()
+}
+
+// resize sets the size of all bubbles based on the size of the terminal.
+func (b *Bubble) resize() []tea.Cmd {
+	var cmds []tea.Cmd
+
+	treeWidth, treeHeight, width, height := b.paneSizes()
+
+	resizeImgCmd := b.image.SetSize(width, height)
+	b.filetree.SetSize(treeWidth, treeHeight-breadcrumbHeight)
+	b.help.SetSize(width, height)
+	b.code.SetSize(width, height)
+	b.renderer.SetSize(width, height)
+	b.picker.SetSize(width, height)
+	b.modal.SetSize(b.width, b.panesHeight())
+	b.keyHelp.SetSize(b.width, b.panesHeight())
+	b.finder.SetSize(b.width, b.panesHeight())
+	b.statusbar.SetSize(b.width)
+
+	contentWidth, _ := b.renderer.Size()
+	switch {
+	case b.state != showTextState:
+	case b.preview.isImage:
+		cmds = append(cmds, b.trackPreview(b.imagePreview(b.preview.path))...)
+	case b.preview.isMarkdown:
+		cmds = append(cmds, b.trackPreview(renderMarkdown(b.preview.path, contentWidth, b.config.Settings.MaxPreviewBytes))...)
+	case b.preview.isTable:
+		cmds = append(cmds, b.trackPreview(b.tablePreview(b.preview.path, contentWidth))...)
+	case b.preview.isHex:
+		cmds = append(cmds, b.trackPreview(hexDump(b.preview.path, contentWidth))...)
+	}
+
+	return append(cmds, resizeImgCmd)
+}
+
+// filetreeBorderHeight returns the number of lines taken up by the top and
+// bottom borders of the filetree, which are hidden rather than removed when
+// borderless is set.
+func (b Bubble) filetreeBorderHeight() (int, int) {
+	border := lipgloss.NormalBorder()
+	if b.config.Settings.Borderless {
+		border = lipgloss.HiddenBorder()
+	}
+
+	return border.GetTopSize(), border.GetBottomSize()
+}
@@ -1,18 +1,28 @@
 package tui
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/finder"
+	"github.com/knipferrc/fm/internal/iconset"
+	"github.com/knipferrc/fm/internal/keyhelp"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/lscolors"
+	"github.com/knipferrc/fm/internal/modal"
+	"github.com/knipferrc/fm/internal/picker"
+	"github.com/knipferrc/fm/internal/renderer"
+	"github.com/knipferrc/fm/internal/strfmt"
 	"github.com/knipferrc/fm/internal/theme"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/knipferrc/teacup/code"
 	"github.com/knipferrc/teacup/filetree"
 	"github.com/knipferrc/teacup/help"
 	"github.com/knipferrc/teacup/image"
-	"github.com/knipferrc/teacup/markdown"
-	"github.com/knipferrc/teacup/pdf"
 	"github.com/knipferrc/teacup/statusbar"
 )
 
@@ -22,38 +32,119 @@ const (
 	idleState sessionState = iota
 	showCodeState
 	showImageState
-	showMarkdownState
-	showPdfState
+	showTextState
+	showPickerState
 )
 
 // Bubble represents the properties of the UI.
 type Bubble struct {
-	filetree  filetree.Bubble
-	help      help.Bubble
-	code      code.Bubble
-	image     image.Bubble
-	markdown  markdown.Bubble
-	pdf       pdf.Bubble
-	statusbar statusbar.Bubble
-	state     sessionState
-	theme     theme.Theme
-	config    config.Config
-	keys      KeyMap
-	activeBox int
+	filetree     filetree.Bubble
+	help         help.Bubble
+	code         code.Bubble
+	image        image.Bubble
+	renderer     renderer.Bubble
+	picker       picker.Bubble
+	modal        modal.Bubble
+	keyHelp      keyhelp.Bubble
+	finder       finder.Bubble
+	statusbar    statusbar.Bubble
+	state        sessionState
+	theme        theme.Theme
+	config       config.Config
+	keys         KeyMap
+	activeBox    int
+	width        int
+	height       int
+	tabs         tabList
+	prompt       promptState
+	confirmation confirmation
+	preview      previewState
+	item         itemInfo
+	dirs         dirInfo
+	status       statusMessage
+	segment      statusSegment
+	dirWatch     watch
+	configWatch  watch
+
+	count            int
+	lastClick        click
+	sizeFormat       strfmt.SizeFormat
+	iconSet          iconset.Set
+	lsColors         lscolors.Palette
+	pickerKind       pickerKind
+	undoStack        []*undoAction
+	clipboard        *fileClipboard
+	archive          *archiveBrowser
+	selection        map[string]struct{}
+	currentDir       string
+	launchDir        string
+	pendingSelection pendingSelection
+	dirCursors       map[string]string
+	operation        *operation
+	quitting         bool
+	showPreview      bool
+	splitRatio       int
+	showDirSummary   bool
+	fileIndex        *fileIndex
 }
 
-// New creates a new instance of the UI.
-func New(startDir, selectionPath string) Bubble {
-	cfg, err := config.ParseConfig()
+// iconSet returns the icon set from the config, which is none while icons
+// are hidden, detecting the set the terminal can show if it is invalid.
+func iconSet(cfg config.Config) iconset.Set {
+	if !cfg.Settings.ShowIcons {
+		return iconset.None
+	}
+
+	set, err := iconset.Parse(cfg.Settings.IconSet)
 	if err != nil {
-		log.Fatal(err)
+		logger.Warn("ignoring the icon set", "err", err)
 	}
 
-	theme := theme.GetTheme(cfg.Theme.AppTheme)
+	return set
+}
+
+// sizeFormat returns the format sizes are shown in according to the config.
+func sizeFormat(cfg config.Config) strfmt.SizeFormat {
+	if cfg.Settings.ExactSizes {
+		return strfmt.ExactBytes
+	}
 
-	syntaxTheme := cfg.Theme.SyntaxTheme.Light
+	return strfmt.HumanReadable
+}
+
+// syntaxTheme returns the syntax theme from the config matching the terminal background.
+func syntaxTheme(cfg config.Config) string {
 	if lipgloss.HasDarkBackground() {
-		syntaxTheme = cfg.Theme.SyntaxTheme.Dark
+		return cfg.Theme.SyntaxTheme.Dark
+	}
+
+	return cfg.Theme.SyntaxTheme.Light
+}
+
+// New creates a new instance of the UI with the given config. If loading the
+// config failed with configErr, the default config it fell back to is
+// reported. If selectFile is set, the file with that name in the start
+// directory is selected and previewed.
+func New(cfg config.Config, configErr error, startDir, selectionPath, selectFile string) Bubble {
+	if configErr != nil {
+		logger.Warn("using the default config", "err", configErr)
+	}
+
+	theme := theme.GetTheme(cfg.Theme.AppTheme)
+
+	keys, warnings := NewKeyMap(cfg.Keybindings)
+	for _, warning := range warnings {
+		logger.Warn("ignoring a keybinding", "reason", warning)
+	}
+
+	launchDir, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	configWatcher, configPath, err := newConfigWatcher()
+	if err != nil {
+		logger.Error("watching the config file failed", "err", err)
 	}
 
 	filetreeModel := filetree.New(
@@ -69,11 +160,29 @@ func New(startDir, selectionPath string) Bubble {
 	filetreeModel.ToggleHelp(false)
 
 	codeModel := code.New(false, cfg.Settings.Borderless, theme.InactiveBoxBorderColor)
-	codeModel.SetSyntaxTheme(syntaxTheme)
+	codeModel.SetSyntaxTheme(syntaxTheme(cfg))
 
 	imageModel := image.New(false, cfg.Settings.Borderless, theme.InactiveBoxBorderColor)
-	markdownModel := markdown.New(false, cfg.Settings.Borderless, theme.InactiveBoxBorderColor)
-	pdfModel := pdf.New(false, cfg.Settings.Borderless, theme.InactiveBoxBorderColor)
+	rendererModel := renderer.New(false, cfg.Settings.Borderless, theme.InactiveBoxBorderColor)
+	rendererModel.SetWrap(cfg.Settings.WrapPreview)
+	rendererModel.SetLineNumberColor(theme.LineNumberColor)
+	pickerModel := picker.New(
+		false,
+		cfg.Settings.Borderless,
+		picker.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+		theme.InactiveBoxBorderColor,
+		theme.SelectedTreeItemColor,
+	)
+	modalModel := modal.New(
+		modal.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
+		},
+		theme.ActiveBoxBorderColor,
+	)
 	statusbarModel := statusbar.New(
 		statusbar.ColorConfig{
 			Foreground: theme.StatusBarSelectedFileForegroundColor,
@@ -93,52 +202,78 @@ func New(startDir, selectionPath string) Bubble {
 		},
 	)
 
-	helpModel := help.New(
-		false,
-		cfg.Settings.Borderless,
-		"Help",
-		help.TitleColor{
+	keyHelpModel := keyhelp.New(
+		"Keybindings",
+		keyhelp.TitleColor{
 			Background: theme.TitleBackgroundColor,
 			Foreground: theme.TitleForegroundColor,
 		},
-		theme.InactiveBoxBorderColor,
-		[]help.Entry{
-			{Key: "ctrl+c, q", Description: "Exit FM"},
-			{Key: "j/up", Description: "Move up"},
-			{Key: "k/down", Description: "Move down"},
-			{Key: "h", Description: "Paginate left in current directory"},
-			{Key: "l", Description: "Paginate right in current directory"},
-			{Key: "space", Description: "Read file or enter directory"},
-			{Key: "G", Description: "Jump to bottom"},
-			{Key: "g", Description: "Jump to top"},
-			{Key: "~", Description: "Go to home directory"},
-			{Key: ".", Description: "Toggle hidden files"},
-			{Key: "y", Description: "Copy file path to clipboard"},
-			{Key: "z", Description: "Zip currently selected tree item"},
-			{Key: "u", Description: "Unzip currently selected tree item"},
-			{Key: "n", Description: "Create new file"},
-			{Key: "N", Description: "Create new directory"},
-			{Key: "x", Description: "Delete currently selected tree item"},
-			{Key: "m", Description: "Move currently selected tree item"},
-			{Key: "enter", Description: "Process command"},
-			{Key: "e", Description: "Edit currently selected tree item"},
-			{Key: "c", Description: "Copy currently selected tree item"},
-			{Key: "esc", Description: "Reset input field"},
-			{Key: "R", Description: "Go to root directory"},
-			{Key: "tab", Description: "Toggle between boxes"},
+		theme.ActiveBoxBorderColor,
+		theme.SelectedTreeItemColor,
+	)
+	keyHelpModel.SetGroups(keys.helpGroups())
+
+	finderModel := finder.New(
+		"Find file",
+		finder.TitleColor{
+			Background: theme.TitleBackgroundColor,
+			Foreground: theme.TitleForegroundColor,
 		},
+		theme.ActiveBoxBorderColor,
+		theme.SelectedTreeItemColor,
 	)
 
-	return Bubble{
+	pickerModel.SetScrollOff(cfg.Settings.ScrollOff)
+	finderModel.SetScrollOff(cfg.Settings.ScrollOff)
+
+	b := Bubble{
 		filetree:  filetreeModel,
-		help:      helpModel,
+		help:      newHelp(cfg, theme, keys),
+		keyHelp:   keyHelpModel,
+		finder:    finderModel,
 		code:      codeModel,
 		image:     imageModel,
-		markdown:  markdownModel,
-		pdf:       pdfModel,
+		renderer:  rendererModel,
+		picker:    pickerModel,
+		modal:     modalModel,
 		statusbar: statusbarModel,
 		theme:     theme,
 		config:    cfg,
-		keys:      DefaultKeyMap(),
+		keys:      keys,
+		tabs:      tabList{list: []tab{{}}},
+		prompt:    promptState{input: textinput.New()},
+		preview:   previewState{imageRenderMode: detectImageRenderMode()},
+		item:      itemInfo{size: -1},
+		dirs:      newDirInfo(),
+		segment: statusSegment{
+			clockTicking: showsClock(cfg),
+			userName:     currentUserName(),
+			hostName:     currentHostName(),
+		},
+		configWatch: watch{
+			watcher: configWatcher,
+			path:    configPath,
+		},
+
+		selection:   make(map[string]struct{}),
+		showPreview: true,
+		splitRatio:  clampSplitRatio(cfg.Settings.SplitRatio),
+		sizeFormat:  sizeFormat(cfg),
+		iconSet:     iconSet(cfg),
+		lsColors:    lscolors.FromEnvironment(),
+		dirCursors:  make(map[string]string),
+		launchDir:   launchDir,
+		pendingSelection: pendingSelection{
+			dir:     startDir,
+			name:    selectFile,
+			preview: true,
+		},
 	}
+
+	if configErr != nil {
+		b.status.message = fmt.Sprintf("Using the default config: %v", configErr)
+		b.status.messageID++
+	}
+
+	return b
 }
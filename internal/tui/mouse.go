@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// filetreeHeaderHeight is the number of lines above the first item of the
+	// filetree taken up by its title and status, besides its top border.
+	filetreeHeaderHeight = 4
+
+	// doubleClickInterval is the maximum time between the two clicks of a double click.
+	doubleClickInterval = 400 * time.Millisecond
+)
+
+// openItemKey is the key the filetree enters directories with.
+var openItemKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+
+// click represents a click on an item of the filetree.
+type click struct {
+	index int
+	time  time.Time
+}
+
+// clickedItem returns the index of the filetree item at the given position,
+// returning false if there is no item at that position.
+func (b Bubble) clickedItem(x, y int) (int, bool) {
+	treeWidth, treeHeight, _, _ := b.paneSizes()
+
+	top := 0
+	if len(b.tabs.list) > 1 {
+		top = tabBarHeight
+	}
+
+	if x < 0 || x >= treeWidth || y < top || y >= top+treeHeight {
+		return 0, false
+	}
+
+	borderTop, _ := b.filetreeBorderHeight()
+
+	y -= top + breadcrumbHeight + borderTop + filetreeHeaderHeight
+	if y < 0 {
+		return 0, false
+	}
+
+	pageSize := b.filetreePageSize()
+	row := y / filetreeRowHeight
+	if row >= pageSize {
+		return 0, false
+	}
+
+	index := b.filetree.Cursor() - b.filetree.Cursor()%pageSize + row
+	if index >= b.filetree.TotalItems() {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// openSelectedItem enters the selected directory or previews the selected
+// file, as if the open key was pressed.
+func (b *Bubble) openSelectedItem() tea.Cmd {
+	var cmd tea.Cmd
+
+	if b.filetree.GetSelectedItem().ShortName() == parentDirectoryName {
+		b.selectCurrentDirInParent()
+	}
+
+	b.filetree, cmd = b.filetree.Update(openItemKey)
+
+	return tea.Batch(append(b.openFile(), cmd)...)
+}
+
+// handleMouse moves the cursor onto the filetree item clicked with the left
+// button, opening it when it is clicked twice in a row. Clicks outside the
+// items of the filetree are ignored.
+func (b *Bubble) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft || b.modal.Visible() || b.keyHelp.Visible() ||
+		b.finder.Visible() || b.prompt.mode != noInputMode || b.filetree.IsFiltering() {
+		return nil
+	}
+
+	index, ok := b.clickedItem(msg.X, msg.Y)
+	if !ok {
+		return nil
+	}
+
+	b.moveCursor(index - b.filetree.Cursor())
+	if b.activeBox != 0 {
+		b.activeBox = 0
+		b.updateActiveBox()
+	}
+
+	now := time.Now()
+	doubleClick := index == b.lastClick.index && now.Sub(b.lastClick.time) < doubleClickInterval
+	b.lastClick = click{index: index, time: now}
+
+	if !doubleClick {
+		return nil
+	}
+
+	b.lastClick = click{}
+
+	return b.openSelectedItem()
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// parentDirectoryName is the name of the filetree item leading to the parent directory.
+const parentDirectoryName = ".."
+
+// pendingSelection represents an item to select once its directory is
+// listed, optionally previewing it once selected.
+type pendingSelection struct {
+	dir     string
+	name    string
+	preview bool
+}
+
+// currentDirectory returns the directory currently listed in the filetree.
+func (b Bubble) currentDirectory() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return b.filetree.GetSelectedItem().CurrentDirectory()
+	}
+
+	return dir
+}
+
+// navigateTo changes the current directory and lists its contents in the filetree.
+func (b *Bubble) navigateTo(dir string) tea.Cmd {
+	if err := os.Chdir(dir); err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	return b.refreshFiletree()
+}
+
+// selectCurrentDirInParent places the cursor on the current directory once
+// the listing of its parent has been read.
+func (b *Bubble) selectCurrentDirInParent() {
+	dir := b.currentDirectory()
+	b.pendingSelection = pendingSelection{
+		dir:  filepath.Dir(dir),
+		name: filepath.Base(dir),
+	}
+}
+
+// goToParent navigates to the parent directory, keeping the cursor on the
+// directory navigated out of.
+func (b *Bubble) goToParent() tea.Cmd {
+	dir := b.currentDirectory()
+	if filepath.Dir(dir) == dir {
+		return nil
+	}
+
+	b.selectCurrentDirInParent()
+
+	return b.navigateTo(filepath.Dir(dir))
+}
+
+// goToPath navigates to the directory at the given path, staying put if
+// it can not be found.
+func (b *Bubble) goToPath(path string) tea.Cmd {
+	dir, err := filesystem.ExpandPath(path)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if err := filesystem.ValidateDirectory(dir); err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	return b.navigateTo(dir)
+}
+
+// goToStartDir navigates to the configured start directory with the cursor
+// on its first item. A relative start directory is resolved against the
+// directory fm was started in.
+func (b *Bubble) goToStartDir() tea.Cmd {
+	dir, err := filesystem.ExpandPathFrom(b.launchDir, b.config.Settings.StartDir)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if err := filesystem.ValidateDirectory(dir); err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if dir == b.currentDirectory() {
+		b.filetree, _ = b.filetree.Update(tea.KeyMsg{Type: tea.KeyHome})
+		return nil
+	}
+
+	// No item can have an empty name, so restoring the cursor moves it to the top.
+	b.dirCursors[dir] = ""
+
+	return b.navigateTo(dir)
+}
+
+// selectItem moves the cursor of the filetree onto the item with the given
+// name by replaying cursor movements, returning false if it isn't listed.
+func (b *Bubble) selectItem(name string) bool {
+	b.filetree, _ = b.filetree.Update(tea.KeyMsg{Type: tea.KeyHome})
+
+	for i := 0; i < b.filetree.TotalItems(); i++ {
+		if b.filetree.GetSelectedItem().ShortName() == name {
+			return true
+		}
+
+		b.filetree, _ = b.filetree.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+
+	return b.filetree.GetSelectedItem().ShortName() == name
+}
+
+// filetreePageSize estimates the number of items the filetree shows at once,
+// each item taking filetreeRowHeight lines below its borders and
+// filetreeChromeHeight lines of title, status, pagination and help.
+func (b Bubble) filetreePageSize() int {
+	_, height, _, _ := b.paneSizes()
+	borderTop, borderBottom := b.filetreeBorderHeight()
+
+	size := (height - breadcrumbHeight - borderTop - borderBottom - filetreeChromeHeight) / filetreeRowHeight
+	if size < 1 {
+		return 1
+	}
+
+	return size
+}
+
+// moveCursor moves the cursor of the filetree by the given number of items
+// by replaying cursor movements, stopping at either end of the listing.
+func (b *Bubble) moveCursor(delta int) {
+	msg := tea.KeyMsg{Type: tea.KeyDown}
+	if delta < 0 {
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+		delta = -delta
+	}
+
+	for i := 0; i < delta; i++ {
+		b.filetree, _ = b.filetree.Update(msg)
+	}
+}
+
+// applyPendingSelection selects the pending item once the listing of its
+// directory has been read by the filetree, previewing it if requested.
+func (b *Bubble) applyPendingSelection() tea.Cmd {
+	if b.pendingSelection.name == "" ||
+		b.filetree.GetSelectedItem().CurrentDirectory() != b.pendingSelection.dir {
+		return nil
+	}
+
+	// The directory is kept so the cursor isn't restored over the selection
+	// once the change of directory is noticed.
+	selection := b.pendingSelection
+	b.pendingSelection = pendingSelection{dir: selection.dir}
+
+	if b.selectItem(selection.name) && selection.preview {
+		return tea.Batch(b.openFile()...)
+	}
+
+	return nil
+}
+
+// restoreCursor moves the cursor back onto the item last selected in a
+// directory visited before, or to the top if that item is gone.
+func (b *Bubble) restoreCursor(dir string) {
+	name, ok := b.dirCursors[dir]
+	if !ok {
+		return
+	}
+
+	if !b.selectItem(name) {
+		b.filetree, _ = b.filetree.Update(tea.KeyMsg{Type: tea.KeyHome})
+	}
+}
+
+// refreshFiletree re-reads the listing of the current directory.
+func (b *Bubble) refreshFiletree() tea.Cmd {
+	return b.filetree.ToggleShowIcons(b.iconSet.TreeIcons())
+}
@@ -0,0 +1,557 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/strfmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyProgressInterval is the minimum time between two copy progress updates.
+const copyProgressInterval = 100 * time.Millisecond
+
+// progressBarWidth is the number of cells used by the progress bar in the status bar.
+const progressBarWidth = 10
+
+// transferMode represents how an item is transferred to its destination.
+type transferMode int
+
+const (
+	copyTransfer transferMode = iota
+	replaceTransfer
+	moveTransfer
+	moveReplaceTransfer
+)
+
+// transfer represents an item copied or moved to a destination.
+type transfer struct {
+	src  string
+	dst  string
+	mode transferMode
+}
+
+// moves returns true if the item is moved rather than copied.
+func (t transfer) moves() bool {
+	return t.mode == moveTransfer || t.mode == moveReplaceTransfer
+}
+
+// replacing returns the transfer replacing an existing destination.
+func (t transfer) replacing() transfer {
+	t.mode = replaceTransfer
+	if t.moves() {
+		t.mode = moveReplaceTransfer
+	}
+
+	return t
+}
+
+// run copies or moves the item, reporting the number of bytes copied as it
+// goes. An existing destination is only replaced in replaceTransfer and
+// moveReplaceTransfer modes.
+func (t transfer) run(ctx context.Context, progress filesystem.ProgressFunc) error {
+	switch t.mode {
+	case replaceTransfer:
+		return filesystem.Replace(ctx, t.src, t.dst, progress)
+	case moveTransfer:
+		return filesystem.Move(ctx, t.src, t.dst, progress)
+	case moveReplaceTransfer:
+		return filesystem.ReplaceByMove(ctx, t.src, t.dst, progress)
+	case copyTransfer:
+	}
+
+	return filesystem.Copy(ctx, t.src, t.dst, progress)
+}
+
+// operation represents a long running file operation.
+type operation struct {
+	description string
+	ch          chan tea.Msg
+	cancel      context.CancelFunc
+	done        int64
+	total       int64
+	tallied     bool
+}
+
+// duplicateName returns the first free path of the form "name (n).ext" next
+// to an item, counting up from 1.
+func duplicateName(src string, isDirectory bool) string {
+	ext := filepath.Ext(src)
+	if isDirectory || ext == filepath.Base(src) {
+		ext = ""
+	}
+
+	base := strings.TrimSuffix(src, ext)
+	for n := 1; ; n++ {
+		dst := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if _, err := os.Lstat(dst); err != nil {
+			return dst
+		}
+	}
+}
+
+// intoItself returns true if a transfer would copy or move an item onto
+// itself or into one of its own subdirectories, which never finishes.
+func (t transfer) intoItself() bool {
+	rel, err := filepath.Rel(t.src, t.dst)
+
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// checkTransfers returns a status message for the first transfer which would
+// copy or move an item into itself, or an empty string if there is none.
+func checkTransfers(transfers []transfer) string {
+	for _, t := range transfers {
+		if !t.intoItself() {
+			continue
+		}
+
+		verb := "copied"
+		if t.moves() {
+			verb = "moved"
+		}
+
+		return fmt.Sprintf("%s can't be %s into itself", filepath.Base(t.src), verb)
+	}
+
+	return ""
+}
+
+// itemsSubject returns the name of the item at the only path, or the number
+// of items if there are several.
+func itemsSubject(paths []string) string {
+	if len(paths) == 1 {
+		return filepath.Base(paths[0])
+	}
+
+	return fmt.Sprintf("%d items", len(paths))
+}
+
+// absPaths returns the absolute form of each path.
+func absPaths(paths []string) ([]string, error) {
+	abs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+
+		abs = append(abs, absPath)
+	}
+
+	return abs, nil
+}
+
+// duplicateSelectedItems copies the selected items, or the item under the
+// cursor if nothing is selected, next to themselves in the background,
+// selecting the first copy once they are made.
+func (b *Bubble) duplicateSelectedItems() tea.Cmd {
+	paths, err := absPaths(b.selectedPaths())
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	transfers := make([]transfer, 0, len(paths))
+	for _, src := range paths {
+		info, err := os.Lstat(src)
+		if err != nil {
+			return b.setStatusMessage(err.Error())
+		}
+
+		transfers = append(transfers, transfer{src: src, dst: duplicateName(src, info.IsDir()), mode: copyTransfer})
+	}
+
+	b.clearSelection()
+
+	return b.startTransfers(transfers)
+}
+
+// moveItemsTo moves items into the directory at path in the background.
+// A single item is moved to path itself if it isn't an existing directory.
+func (b *Bubble) moveItemsTo(paths []string, path string) tea.Cmd {
+	if strings.TrimSpace(path) == "" {
+		return b.setStatusMessage("A destination is required")
+	}
+
+	srcs, err := absPaths(paths)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	dst, err := filesystem.ExpandPathFrom(b.currentDirectory(), path)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	info, err := os.Stat(dst)
+	isDir := err == nil && info.IsDir()
+	if !isDir && len(srcs) > 1 {
+		return b.setStatusMessage(fmt.Sprintf("%s is not a directory", dst))
+	}
+
+	transfers := make([]transfer, 0, len(srcs))
+	for _, src := range srcs {
+		target := dst
+		if isDir {
+			target = filepath.Join(dst, filepath.Base(src))
+		}
+
+		if target != src {
+			transfers = append(transfers, transfer{src: src, dst: target, mode: moveTransfer})
+		}
+	}
+
+	if len(transfers) == 0 {
+		return b.setStatusMessage(fmt.Sprintf("%s already there", itemsSubject(srcs)))
+	}
+
+	b.clearSelection()
+
+	return b.transferItems(transfers)
+}
+
+// saveAs copies a file to a new name next to it in the background, asking
+// before overwriting an existing item.
+func (b *Bubble) saveAs(src, name string) tea.Cmd {
+	if name == "" {
+		return b.setStatusMessage("A name is required")
+	}
+
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return b.setStatusMessage(err.Error())
+	}
+
+	dst := filepath.Join(filepath.Dir(src), name)
+	if dst == src {
+		return b.setStatusMessage(fmt.Sprintf("%s can't be saved over itself", filepath.Base(src)))
+	}
+
+	return b.transferItems([]transfer{{src: src, dst: dst, mode: copyTransfer}})
+}
+
+// transferItems copies or moves items in the background. If some of the
+// destinations exist already, it asks whether to overwrite them, to skip
+// those items or to give them the first free "name (n)" name instead.
+func (b *Bubble) transferItems(transfers []transfer) tea.Cmd {
+	if message := checkTransfers(transfers); message != "" {
+		return b.setStatusMessage(message)
+	}
+
+	var existing []int
+	for i, t := range transfers {
+		if _, err := os.Lstat(t.dst); err == nil {
+			existing = append(existing, i)
+		}
+	}
+
+	if len(existing) == 0 {
+		return b.startTransfers(transfers)
+	}
+
+	message := fmt.Sprintf("%s already exists.", filepath.Base(transfers[existing[0]].dst))
+	if len(existing) > 1 {
+		message = fmt.Sprintf("%d items already exist.", len(existing))
+	}
+
+	b.choose("Overwrite", message, []choice{
+		{key: "o", label: "overwrite", action: func(b *Bubble) tea.Cmd {
+			for _, i := range existing {
+				transfers[i] = transfers[i].replacing()
+			}
+
+			return b.startTransfers(transfers)
+		}},
+		{key: "s", label: "skip", action: func(b *Bubble) tea.Cmd {
+			return b.skipTransfers(transfers, existing)
+		}},
+		{key: "r", label: "rename", action: func(b *Bubble) tea.Cmd {
+			for _, i := range existing {
+				info, err := os.Lstat(transfers[i].src)
+				transfers[i].dst = duplicateName(transfers[i].dst, err == nil && info.IsDir())
+			}
+
+			return b.startTransfers(transfers)
+		}},
+	})
+
+	return nil
+}
+
+// skipTransfers starts all transfers but the skipped ones.
+func (b *Bubble) skipTransfers(transfers []transfer, skipped []int) tea.Cmd {
+	var (
+		kept    []transfer
+		skipSrc []string
+	)
+
+	for i, t := range transfers {
+		if len(skipped) > 0 && skipped[0] == i {
+			skipped = skipped[1:]
+			skipSrc = append(skipSrc, t.src)
+
+			continue
+		}
+
+		kept = append(kept, t)
+	}
+
+	if len(kept) == 0 {
+		return b.setStatusMessage(fmt.Sprintf("Skipped %s", itemsSubject(skipSrc)))
+	}
+
+	return b.startTransfers(kept)
+}
+
+// startTransfers starts copying or moving items in the background.
+func (b *Bubble) startTransfers(transfers []transfer) tea.Cmd {
+	if b.operation != nil {
+		return b.setStatusMessage("Another operation is still running")
+	}
+
+	if message := checkTransfers(transfers); message != "" {
+		return b.setStatusMessage(message)
+	}
+
+	srcs := make([]string, 0, len(transfers))
+	for _, t := range transfers {
+		srcs = append(srcs, t.src)
+	}
+
+	description := fmt.Sprintf("Copying %s", itemsSubject(srcs))
+	if transfers[0].moves() {
+		description = fmt.Sprintf("Moving %s", itemsSubject(srcs))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.operation = &operation{
+		description: description,
+		ch:          make(chan tea.Msg, 1),
+		cancel:      cancel,
+	}
+
+	return transferItemsCmd(ctx, transfers, b.operation.ch)
+}
+
+// quit quits right away unless an operation is running, in which case it
+// asks before cancelling the operation and quits once it has cleaned up.
+func (b *Bubble) quit() tea.Cmd {
+	if b.operation == nil {
+		return tea.Quit
+	}
+
+	b.confirm(
+		"Quit",
+		fmt.Sprintf("%s is still running. Cancel it and quit?", b.operation.description),
+		func(b *Bubble) tea.Cmd {
+			if b.operation == nil {
+				return tea.Quit
+			}
+
+			b.quitting = true
+			b.operation.cancel()
+
+			return nil
+		},
+	)
+
+	return nil
+}
+
+// progressView returns the progress of the running operation with sizes in the given format.
+func (o operation) progressView(sizeFormat strfmt.SizeFormat) string {
+	if !o.tallied {
+		return fmt.Sprintf("%s, calculating size...", o.description)
+	}
+
+	percent := 100
+	if o.total > 0 {
+		percent = int(o.done * 100 / o.total)
+	}
+
+	filled := percent * progressBarWidth / 100
+
+	return fmt.Sprintf(
+		"%s %s%s %d%% (%s/%s)",
+		o.description,
+		strings.Repeat("█", filled),
+		strings.Repeat("░", progressBarWidth-filled),
+		percent,
+		strfmt.FormatSize(o.done, sizeFormat),
+		strfmt.FormatSize(o.total, sizeFormat),
+	)
+}
+
+type copyProgressMsg struct {
+	done  int64
+	total int64
+}
+
+type operationFinishedMsg struct {
+	result tea.Msg
+}
+
+// waitForOperationCmd waits for the next message sent by a running operation.
+func waitForOperationCmd(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// transferItemsCmd copies or moves files and directories in the background,
+// sending their combined progress on ch and going on with the rest if one of
+// them fails, even while adding up their sizes. A cancelled copy removes the
+// partially copied destination. Moves can be undone.
+func transferItemsCmd(ctx context.Context, transfers []transfer, ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		go func() {
+			var (
+				sizes      = make([]int64, len(transfers))
+				skipped    = make([]bool, len(transfers))
+				done       []transfer
+				failed     []error
+				total      int64
+				finished   int64
+				lastReport time.Time
+				cancelled  bool
+			)
+
+			for i, t := range transfers {
+				size, err := filesystem.Size(ctx, t.src)
+				if errors.Is(err, context.Canceled) {
+					cancelled = true
+					break
+				}
+
+				if err != nil {
+					logger.Error("adding up the size failed", "src", t.src, "err", err)
+					failed = append(failed, err)
+					skipped[i] = true
+
+					continue
+				}
+
+				sizes[i] = size
+				total += size
+			}
+
+			// Progress isn't sent once the operation is cancelled, so that
+			// nothing blocks on a channel which may no longer be read. Only
+			// the result is, since it is always waited for.
+			if !cancelled {
+				select {
+				case ch <- copyProgressMsg{total: total}:
+				case <-ctx.Done():
+				}
+			}
+
+			for i, t := range transfers {
+				if cancelled {
+					break
+				}
+
+				if skipped[i] {
+					continue
+				}
+
+				err := t.run(ctx, func(copied int64) {
+					if time.Since(lastReport) < copyProgressInterval {
+						return
+					}
+
+					lastReport = time.Now()
+					select {
+					case ch <- copyProgressMsg{done: finished + copied, total: total}:
+					default:
+					}
+				})
+
+				finished += sizes[i]
+
+				if errors.Is(err, context.Canceled) {
+					cancelled = true
+					break
+				}
+
+				if err != nil {
+					logger.Error("transferring failed", "src", t.src, "dst", t.dst, "err", err)
+					failed = append(failed, err)
+
+					continue
+				}
+
+				done = append(done, t)
+			}
+
+			ch <- operationFinishedMsg{result: transferResult(transfers, done, failed, cancelled)}
+		}()
+
+		return <-ch
+	}
+}
+
+// transferResult reports which of the transfers were done, selecting the
+// first item copied or moved and recording moves so that they can be undone.
+func transferResult(transfers, done []transfer, failed []error, cancelled bool) tea.Msg {
+	doing, did := "copying", "Copied"
+	if transfers[0].moves() {
+		doing, did = "moving", "Moved"
+	}
+
+	var msg fileOperationMsg
+	if len(done) > 0 {
+		msg.selectPath = done[0].dst
+
+		if done[0].moves() {
+			msg.undo = moveUndo(done)
+		}
+	}
+
+	srcs := make([]string, 0, len(done))
+	for _, t := range done {
+		srcs = append(srcs, t.src)
+	}
+
+	switch {
+	case cancelled:
+		all := make([]string, 0, len(transfers))
+		for _, t := range transfers {
+			all = append(all, t.src)
+		}
+
+		msg.message = fmt.Sprintf("Cancelled %s %s", doing, itemsSubject(all))
+		if len(done) > 0 {
+			msg.message = fmt.Sprintf("%s after %s %s", msg.message, strings.ToLower(did), itemsSubject(srcs))
+		}
+	case len(done) == 0 && len(failed) == 1:
+		msg.message = failed[0].Error()
+	case len(done) == 0:
+		msg.message = fmt.Sprintf("%s nothing, %d items failed: %v", did, len(failed), failed[0])
+	case len(done) == 1 && !done[0].moves():
+		msg.message = fmt.Sprintf("%s %s to %s", did, itemsSubject(srcs), filepath.Base(done[0].dst))
+	default:
+		msg.message = fmt.Sprintf("%s %s to %s", did, itemsSubject(srcs), filepath.Dir(done[0].dst))
+	}
+
+	if len(done) > 0 && len(failed) > 0 {
+		msg.message = fmt.Sprintf("%s, %d failed: %v", msg.message, len(failed), failed[0])
+	}
+
+	return msg
+}
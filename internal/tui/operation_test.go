@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTransferItemsGoesOnAfterSizeError(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(src, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	transfers := []transfer{
+		{src: filepath.Join(dir, "missing.txt"), dst: filepath.Join(dir, "out", "missing.txt")},
+		{src: src, dst: filepath.Join(dir, "b.txt")},
+	}
+
+	ch := make(chan tea.Msg, 1)
+	msg := transferItemsCmd(context.Background(), transfers, ch)()
+	for {
+		if finished, ok := msg.(operationFinishedMsg); ok {
+			msg = finished.result
+			break
+		}
+
+		msg = <-ch
+	}
+
+	result, ok := msg.(fileOperationMsg)
+	if !ok {
+		t.Fatalf("got %T, want fileOperationMsg", msg)
+	}
+
+	if !strings.Contains(result.message, "1 failed") {
+		t.Errorf("message %q doesn't report the failed item", result.message)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("the rest wasn't copied: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/picker"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pickerKind int
+
+const (
+	bookmarksPicker pickerKind = iota
+	archivePicker
+	templatesPicker
+	mountsPicker
+)
+
+// openPicker shows the picker in the right box and focuses it.
+func (b *Bubble) openPicker(kind pickerKind, title string, items []picker.Item) {
+	b.state = showPickerState
+	b.pickerKind = kind
+	b.picker.SetItems(title, items)
+	b.activeBox = 1
+	b.updateActiveBox()
+}
+
+// closePicker hides the picker and focuses the filetree.
+func (b *Bubble) closePicker() {
+	b.state = idleState
+	b.activeBox = 0
+	b.updateActiveBox()
+}
+
+// handlePickerKey handles keys while the picker is focused, returning false
+// if the key should be handled as usual. Only the keys to quit, to switch
+// boxes and to show the help are, any other key only moves the picker's
+// cursor so that it can't act on the filetree selection hidden behind it.
+func (b *Bubble) handlePickerKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	selectedItem, ok := b.picker.SelectedItem()
+
+	switch {
+	case key.Matches(msg, b.keys.Cancel):
+		if b.pickerKind == archivePicker {
+			b.closeArchive()
+			return nil, true
+		}
+
+		b.closePicker()
+		return nil, true
+	case key.Matches(msg, b.keys.Submit):
+		b.closePicker()
+		if !ok {
+			return nil, true
+		}
+
+		switch b.pickerKind {
+		case bookmarksPicker, mountsPicker:
+			return b.navigateTo(selectedItem.Value), true
+		case archivePicker:
+			return b.openArchiveItem(selectedItem.Value), true
+		case templatesPicker:
+			b.prompt.source = selectedItem.Value
+			b.startInput(templateInputMode, fmt.Sprintf("New file from %s: ", selectedItem.Title), selectedItem.Title)
+			b.placeCursorBeforeExtension(selectedItem.Title)
+		}
+
+		return nil, true
+	case key.Matches(msg, b.keys.ParentDirectory):
+		if b.pickerKind != archivePicker {
+			break
+		}
+
+		if b.archive.dir == "" {
+			b.closeArchive()
+			return nil, true
+		}
+
+		return listArchiveDirCmd(b.archive.path, b.archive.parentDir(), b.config.Settings.CaseSensitiveSort), true
+	case key.Matches(msg, b.keys.Delete):
+		if ok && b.pickerKind == bookmarksPicker {
+			return removeBookmarkCmd(selectedItem.Value), true
+		}
+	}
+
+	if key.Matches(msg, b.keys.Quit, b.keys.Exit, b.keys.ToggleBox, b.keys.Help) {
+		return nil, false
+	}
+
+	var cmd tea.Cmd
+	b.picker, cmd = b.picker.Update(msg)
+
+	return cmd, true
+}
+
+type mountsMsg []filesystem.Mount
+
+// listMountsCmd lists the mounted filesystems, or the drives on Windows.
+func listMountsCmd() tea.Cmd {
+	return func() tea.Msg {
+		mounts, err := filesystem.ListMounts()
+		if err != nil {
+			return statusMessageMsg(fmt.Sprintf("Unable to list mounts: %v", err))
+		}
+
+		return mountsMsg(mounts)
+	}
+}
@@ -0,0 +1,419 @@
+package tui
+
+import (
+	"image"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/previewer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type imageRenderMode int
+
+const (
+	asciiRenderMode imageRenderMode = iota
+	trueColorRenderMode
+)
+
+// previewState represents the file shown in the preview and the previews
+// still being loaded.
+type previewState struct {
+	path            string
+	isImage         bool
+	isMarkdown      bool
+	isTable         bool
+	isHex           bool
+	imageRenderMode imageRenderMode
+	images          imageCache
+	gen             int
+	autoPath        string
+	autoSeq         int
+}
+
+// detectImageRenderMode returns the true color render mode if the terminal
+// advertises 24-bit color support, falling back to ASCII otherwise.
+func detectImageRenderMode() imageRenderMode {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return trueColorRenderMode
+	default:
+		return asciiRenderMode
+	}
+}
+
+var markdownExtensions = []string{".md", ".markdown"}
+
+var forbiddenExtensions = []string{
+	".FCStd",
+	".gif",
+	".rar",
+	".webm",
+	".sqlite",
+	".sqlite-shm",
+	".sqlite-wal",
+	".DS_Store",
+	".db",
+	".data",
+	".plist",
+	".webp",
+	".img",
+}
+
+type previewResultMsg struct {
+	gen int
+	msg tea.Msg
+}
+
+type autoPreviewMsg struct {
+	seq int
+}
+
+type fileContentMsg struct {
+	path    string
+	content string
+}
+
+type codeContentMsg struct {
+	path    string
+	content string
+}
+
+type markdownContentMsg struct {
+	path    string
+	content string
+}
+
+type pdfContentMsg struct {
+	path    string
+	content string
+}
+
+type imageMetaMsg struct {
+	path    string
+	content string
+}
+
+type imageDecodedMsg struct {
+	key imageKey
+	img image.Image
+	err error
+}
+
+type hexContentMsg struct {
+	path    string
+	content string
+}
+
+type tableContentMsg struct {
+	path    string
+	content string
+}
+
+type dirListingMsg struct {
+	path    string
+	content string
+}
+
+type archiveContentMsg struct {
+	path    string
+	content string
+}
+
+type previewCommandMsg struct {
+	path    string
+	content string
+	err     error
+}
+
+// resetViewports goes to the top of all bubbles viewports.
+func (b *Bubble) resetViewports() {
+	b.code.GotoTop()
+	b.help.GotoTop()
+	b.image.GotoTop()
+	b.renderer.GotoTop()
+}
+
+// openFile opens the currently selected file, previewing it with the command
+// configured for its extension if there is one.
+func (b *Bubble) openFile() []tea.Cmd {
+	var cmds []tea.Cmd
+
+	selectedFile := b.filetree.GetSelectedItem()
+	if selectedFile.IsDirectory() {
+		return nil
+	}
+
+	cmds = append(cmds, b.setPreviewVisible(true)...)
+	b.resetViewports()
+
+	if command, ok := previewer.Command(b.config.Previewers, selectedFile.FileExtension()); ok {
+		b.preview.isImage = false
+		b.preview.isMarkdown = false
+		b.preview.isTable = false
+		b.preview.isHex = false
+		b.renderer.SetLineNumbers(false)
+		b.state = showTextState
+		b.preview.path = selectedFile.FileName()
+
+		cmds = append(cmds, b.trackPreview(runPreviewCommand(command, selectedFile.FileName()))...)
+
+		return append(cmds, b.renderer.StartLoading())
+	}
+
+	return append(cmds, b.previewFile(selectedFile.FileName(), selectedFile.FileExtension())...)
+}
+
+// trackPreview starts a new preview generation, wrapping the commands
+// reading it so that their results are dropped once a newer preview started.
+func (b *Bubble) trackPreview(cmds ...tea.Cmd) []tea.Cmd {
+	b.preview.gen++
+	gen := b.preview.gen
+
+	tracked := make([]tea.Cmd, 0, len(cmds))
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+
+		cmd := cmd
+		tracked = append(tracked, func() tea.Msg {
+			return previewResultMsg{gen: gen, msg: cmd()}
+		})
+	}
+
+	return tracked
+}
+
+// imagePreview renders an image for the text preview, decoding it in the
+// background unless it is cached.
+func (b *Bubble) imagePreview(path string) tea.Cmd {
+	key, err := newImageKey(path)
+	if err != nil {
+		return func() tea.Msg {
+			return fileContentMsg{path: path, content: err.Error()}
+		}
+	}
+
+	if img, ok := b.preview.images.get(key); ok {
+		width, height := b.renderer.Size()
+		return renderImage(path, img, width, height)
+	}
+
+	return decodeImageCmd(key, b.config.Settings.MaxImagePixels)
+}
+
+// imageDecoded caches a decoded image and renders it if it is still
+// previewed.
+func (b *Bubble) imageDecoded(msg imageDecodedMsg) []tea.Cmd {
+	if msg.err == nil {
+		b.preview.images.add(msg.key, msg.img)
+	}
+
+	if !b.preview.isImage || msg.key.path != b.preview.path {
+		return nil
+	}
+
+	if msg.err != nil && isSVG(msg.key.path) {
+		logger.Warn("drawing the SVG failed", "path", msg.key.path, "err", msg.err)
+		b.preview.isImage = false
+
+		return b.trackPreview(readFileContent(msg.key.path, b.config.Settings.MaxPreviewBytes))
+	}
+
+	if msg.err != nil {
+		b.renderer.SetContent(msg.err.Error())
+		return nil
+	}
+
+	width, height := b.renderer.Size()
+
+	return b.trackPreview(renderImage(msg.key.path, msg.img, width, height))
+}
+
+// previewFile shows the built-in preview of a file with the given extension.
+// The commands reading it are tracked, while the spinner isn't, as its ticks
+// keep running when a newer preview starts loading.
+func (b *Bubble) previewFile(path, ext string) []tea.Cmd {
+	var cmds []tea.Cmd
+
+	b.preview.isImage = false
+	b.preview.isMarkdown = false
+	b.preview.isTable = false
+	b.preview.isHex = false
+	b.renderer.SetLineNumbers(false)
+
+	switch {
+	case (ext == ".png" || ext == ".jpg" || ext == ".jpeg") && b.config.Settings.ShowImageMetadata:
+		b.state = showTextState
+		b.preview.path = path
+		cmds = append(cmds, readImageMetadata(path))
+	case (ext == ".png" || ext == ".jpg" || ext == ".jpeg") &&
+		b.preview.imageRenderMode == trueColorRenderMode:
+		b.state = showTextState
+		b.preview.path = path
+		b.preview.isImage = true
+		cmds = append(cmds, b.imagePreview(path))
+	case strings.EqualFold(ext, ".svg") && b.config.Settings.RenderSVG &&
+		b.preview.imageRenderMode == trueColorRenderMode:
+		b.state = showTextState
+		b.preview.path = path
+		b.preview.isImage = true
+		cmds = append(cmds, b.imagePreview(path))
+	case ext == ".png" || ext == ".jpg" || ext == ".jpeg":
+		b.state = showImageState
+		readFileCmd := b.image.SetFileName(path)
+		cmds = append(cmds, readFileCmd)
+	case contains(markdownExtensions, ext) && b.config.Settings.PrettyMarkdown:
+		b.state = showTextState
+		b.preview.path = path
+		b.preview.isMarkdown = true
+		width, _ := b.renderer.Size()
+		cmds = append(cmds, renderMarkdown(path, width, b.config.Settings.MaxPreviewBytes))
+	case contains(markdownExtensions, ext):
+		b.state = showTextState
+		b.preview.path = path
+		b.renderer.SetLineNumbers(b.config.Settings.PreviewLineNumbers)
+		cmds = append(cmds, readFileContent(path, b.config.Settings.MaxPreviewBytes))
+	case filesystem.IsTable(path):
+		b.state = showTextState
+		b.preview.path = path
+		b.preview.isTable = true
+		width, _ := b.renderer.Size()
+		cmds = append(cmds, b.tablePreview(path, width))
+	case ext == ".json":
+		b.state = showTextState
+		b.preview.path = path
+		b.renderer.SetLineNumbers(b.config.Settings.PreviewLineNumbers)
+
+		style := ""
+		if b.config.Settings.SyntaxHighlighting {
+			style = syntaxTheme(b.config)
+		}
+
+		cmds = append(cmds, formatJSON(path, style, b.config.Settings.MaxPreviewBytes))
+	case ext == ".pdf":
+		b.state = showTextState
+		b.preview.path = path
+		cmds = append(cmds, readPDFContent(path))
+	case filesystem.IsArchive(path):
+		b.state = showTextState
+		b.preview.path = path
+		cmds = append(cmds, readArchiveContent(path, b.sizeFormat))
+	case filesystem.IsOfficeDocument(path):
+		b.state = showTextState
+		b.preview.path = path
+		cmds = append(cmds, readOfficeProperties(path, b.sizeFormat))
+	case contains(forbiddenExtensions, ext):
+		return nil
+	case filesystem.IsBinary(path):
+		b.state = showTextState
+		b.preview.path = path
+		b.preview.isHex = true
+		width, _ := b.renderer.Size()
+		cmds = append(cmds, hexDump(path, width))
+	case !b.config.Settings.SyntaxHighlighting:
+		b.state = showTextState
+		b.preview.path = path
+		b.renderer.SetLineNumbers(b.config.Settings.PreviewLineNumbers)
+		cmds = append(cmds, readFileContent(path, b.config.Settings.MaxPreviewBytes))
+	case b.config.Settings.PreviewLineNumbers || b.exceedsPreviewLimit(path) || !isUTF8(path):
+		b.state = showTextState
+		b.preview.path = path
+		b.renderer.SetLineNumbers(b.config.Settings.PreviewLineNumbers)
+		cmds = append(cmds, highlightCode(path, syntaxTheme(b.config), b.config.Settings.MaxPreviewBytes))
+	default:
+		b.state = showCodeState
+		readFileCmd := b.code.SetFileName(path)
+		cmds = append(cmds, readFileCmd)
+	}
+
+	tracked := b.trackPreview(cmds...)
+	if b.state == showTextState {
+		tracked = append(tracked, b.renderer.StartLoading())
+	}
+
+	return tracked
+}
+
+// exceedsPreviewLimit returns true if a file is larger than max_preview_bytes,
+// in which case it can't be left to the code bubble as that reads the whole file.
+func (b Bubble) exceedsPreviewLimit(path string) bool {
+	if b.config.Settings.MaxPreviewBytes <= 0 {
+		return false
+	}
+
+	info, err := os.Stat(path)
+
+	return err == nil && info.Size() > b.config.Settings.MaxPreviewBytes
+}
+
+// isUTF8 returns true if a text file is encoded in UTF-8, otherwise it can't
+// be left to the code bubble as that doesn't convert it.
+func isUTF8(path string) bool {
+	encoding, _ := filesystem.DetectEncoding(path)
+
+	return encoding == filesystem.UTF8
+}
+
+// tablePreview renders a CSV or TSV file as a table styled with the theme.
+func (b Bubble) tablePreview(path string, width int) tea.Cmd {
+	return renderTable(path, width, b.theme.SelectedTreeItemColor, b.theme.InactiveBoxBorderColor)
+}
+
+// updateAutoPreview waits for the cursor to settle on a newly selected item
+// before previewing it when auto_preview is enabled, so moving quickly
+// doesn't read every item passed on the way.
+func (b *Bubble) updateAutoPreview() tea.Cmd {
+	selectedFile := b.filetree.GetSelectedItem()
+	if !b.config.Settings.AutoPreview || selectedFile.FileName() == b.preview.autoPath {
+		return nil
+	}
+
+	b.preview.autoPath = selectedFile.FileName()
+	b.preview.autoSeq++
+
+	delay := time.Duration(b.config.Settings.AutoPreviewDelay) * time.Millisecond
+	if delay < 0 {
+		delay = 0
+	}
+
+	return autoPreviewAfter(b.preview.autoSeq, delay)
+}
+
+// autoPreview previews the item under the cursor, listing the entries of a
+// directory. Nothing is previewed while the right box is hidden or in use.
+func (b *Bubble) autoPreview() []tea.Cmd {
+	selectedFile := b.filetree.GetSelectedItem()
+	if !b.showPreview || b.activeBox != 0 || b.state == showPickerState ||
+		selectedFile.FileName() == "" || selectedFile.ShortName() == parentDirectoryName {
+		return nil
+	}
+
+	if !selectedFile.IsDirectory() {
+		return b.openFile()
+	}
+
+	b.resetViewports()
+	b.preview.isImage = false
+	b.preview.isMarkdown = false
+	b.preview.isTable = false
+	b.preview.isHex = false
+	b.renderer.SetLineNumbers(false)
+	b.state = showTextState
+	b.preview.path = selectedFile.FileName()
+
+	return b.trackPreview(readDirListing(b.preview.path, b.lsColors, b.sizeFormat, b.config.Settings.CaseSensitiveSort))
+}
+
+// autoPreviewAfter previews the selected item once the cursor stayed on it
+// for the given delay, unless it moved on in the meantime.
+func autoPreviewAfter(seq int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return autoPreviewMsg{seq: seq}
+	})
+}
@@ -0,0 +1,494 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the jpeg decoder.
+	_ "image/png"  // Register the png decoder.
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knipferrc/fm/internal/exif"
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/lscolors"
+	"github.com/knipferrc/fm/internal/previewer"
+	"github.com/knipferrc/fm/internal/renderer"
+	"github.com/knipferrc/fm/internal/strfmt"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/formatters"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// pdfPreviewPages is the number of pages extracted from a PDF for its preview.
+	pdfPreviewPages = 20
+
+	// pdfPreviewTimeout is how long extracting the text of a PDF may take.
+	pdfPreviewTimeout = 5 * time.Second
+
+	// hexPreviewBytes is the number of bytes read from a binary file for its hex dump.
+	hexPreviewBytes = 4096
+
+	// tablePreviewRows is the number of rows parsed from a CSV or TSV file for its preview.
+	tablePreviewRows = 1000
+
+	// truncatedMarker is appended to the preview of a file cut off at max_preview_bytes.
+	truncatedMarker = "…(truncated)"
+)
+
+// readPreviewContent reads up to maxBytes bytes of a file for its preview,
+// converted to UTF-8 and reporting whether the file was cut off.
+func readPreviewContent(path string, maxBytes int64) (string, bool, error) {
+	encoding, _ := filesystem.DetectEncoding(path)
+
+	data, truncated, err := filesystem.ReadLimited(path, maxBytes)
+	if err != nil {
+		return "", false, err
+	}
+
+	return filesystem.DecodeText(data, encoding), truncated, nil
+}
+
+// withTruncatedMarker appends truncatedMarker to the preview of a file which was cut off.
+func withTruncatedMarker(content string, truncated bool) string {
+	if !truncated {
+		return content
+	}
+
+	return fmt.Sprintf("%s\n%s", strings.TrimSuffix(content, "\n"), truncatedMarker)
+}
+
+// readFileContent reads up to maxBytes bytes of a file to be shown as plain
+// text, describing the file instead if it is binary.
+func readFileContent(path string, maxBytes int64) tea.Cmd {
+	return func() tea.Msg {
+		if filesystem.IsBinary(path) {
+			return fileContentMsg{path: path, content: describeBinaryFile(path)}
+		}
+
+		content, truncated, err := readPreviewContent(path, maxBytes)
+		if err != nil {
+			return fileContentMsg{path: path, content: err.Error()}
+		}
+
+		return fileContentMsg{path: path, content: withTruncatedMarker(content, truncated)}
+	}
+}
+
+// highlightCode reads up to maxBytes bytes of a source file and highlights
+// its syntax with the given chroma style, picking the lexer by the file name
+// or, failing that, by its content.
+func highlightCode(path, syntaxTheme string, maxBytes int64) tea.Cmd {
+	return func() tea.Msg {
+		content, truncated, err := readPreviewContent(path, maxBytes)
+		if err != nil {
+			return codeContentMsg{path: path, content: err.Error()}
+		}
+
+		content = highlight(path, content, syntaxTheme)
+
+		return codeContentMsg{path: path, content: withTruncatedMarker(content, truncated)}
+	}
+}
+
+// highlight highlights the syntax of the content of a file with the given
+// chroma style, picking the lexer by the file name or, failing that, by the
+// content. The content is returned as it is if it can't be highlighted.
+func highlight(path, content, syntaxTheme string) string {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		lexer = lexers.Analyse(content)
+	}
+
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return content
+	}
+
+	var highlighted strings.Builder
+	if err := formatters.TTY256.Format(&highlighted, styles.Get(syntaxTheme), iterator); err != nil {
+		return content
+	}
+
+	return highlighted.String()
+}
+
+// formatJSON reads up to maxBytes bytes of a JSON file and indents it,
+// highlighting its syntax with the given chroma style unless syntaxTheme is
+// empty. Invalid or cut off JSON is shown as it is, invalid JSON followed by
+// the parse error.
+func formatJSON(path, syntaxTheme string, maxBytes int64) tea.Cmd {
+	return func() tea.Msg {
+		content, truncated, err := readPreviewContent(path, maxBytes)
+		if err != nil {
+			return codeContentMsg{path: path, content: err.Error()}
+		}
+
+		var parseErr error
+		if !truncated {
+			var indented bytes.Buffer
+			if parseErr = json.Indent(&indented, []byte(content), "", "  "); parseErr == nil {
+				content = indented.String()
+			}
+		}
+
+		if syntaxTheme != "" {
+			content = highlight(path, content, syntaxTheme)
+		}
+
+		content = withTruncatedMarker(content, truncated)
+		if parseErr != nil {
+			content = fmt.Sprintf("%s\n\nInvalid JSON: %v", strings.TrimSuffix(content, "\n"), parseErr)
+		}
+
+		return codeContentMsg{path: path, content: content}
+	}
+}
+
+// runPreviewCommand previews a file with the output of an external command.
+func runPreviewCommand(command, path string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := previewer.Run(command, path)
+
+		return previewCommandMsg{path: path, content: content, err: err}
+	}
+}
+
+// describeBinaryFile returns a placeholder with the size and MIME type of a binary file.
+func describeBinaryFile(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err.Error()
+	}
+
+	contentType, err := filesystem.ContentType(path)
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("Binary file (%d bytes, %s)", info.Size(), contentType)
+}
+
+// cameraName returns the make and model of the camera an image was taken
+// with, leaving out the make when the model already starts with it.
+func cameraName(meta exif.Metadata) string {
+	if strings.HasPrefix(strings.ToLower(meta.Model), strings.ToLower(meta.Make)) {
+		return meta.Model
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s", meta.Make, meta.Model))
+}
+
+// readImageMetadata describes the format and dimensions of an image, along
+// with the camera, the date taken and the GPS position from its EXIF metadata.
+func readImageMetadata(path string) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Open(path)
+		if err != nil {
+			return imageMetaMsg{path: path, content: err.Error()}
+		}
+		defer f.Close()
+
+		config, format, err := image.DecodeConfig(f)
+		if err != nil {
+			return imageMetaMsg{path: path, content: err.Error()}
+		}
+
+		fields := [][2]string{
+			{"Format", strings.ToUpper(format)},
+			{"Dimensions", fmt.Sprintf("%dx%d", config.Width, config.Height)},
+		}
+
+		meta, err := exif.Read(path)
+		if err != nil && !errors.Is(err, exif.ErrNoMetadata) {
+			logger.Error("reading the image metadata failed", "path", path, "err", err)
+		}
+
+		if camera := cameraName(meta); camera != "" {
+			fields = append(fields, [2]string{"Camera", camera})
+		}
+
+		if meta.DateTaken != "" {
+			taken := meta.DateTaken
+			if t, err := time.Parse("2006:01:02 15:04:05", taken); err == nil {
+				taken = t.Format("2006-01-02 15:04:05")
+			}
+
+			fields = append(fields, [2]string{"Taken", taken})
+		}
+
+		if meta.HasGPS {
+			fields = append(fields, [2]string{"GPS", fmt.Sprintf("%.6f, %.6f", meta.Latitude, meta.Longitude)})
+		}
+
+		var content strings.Builder
+		for _, field := range fields {
+			fmt.Fprintf(&content, "%-12s%s\n", field[0], field[1])
+		}
+
+		return imageMetaMsg{path: path, content: content.String()}
+	}
+}
+
+// readOfficeProperties describes an Office document by its title, author,
+// dates and counts, falling back to listing its entries like an archive if
+// it doesn't hold the properties of a document.
+func readOfficeProperties(path string, sizeFormat strfmt.SizeFormat) tea.Cmd {
+	return func() tea.Msg {
+		props, err := filesystem.ReadOfficeProperties(path)
+		if err != nil {
+			logger.Warn("reading the document properties failed", "path", path, "err", err)
+
+			return readArchiveContent(path, sizeFormat)()
+		}
+
+		fields := [][2]string{
+			{"Title", props.Title},
+			{"Subject", props.Subject},
+			{"Author", props.Creator},
+			{"Keywords", props.Keywords},
+			{"Description", props.Description},
+			{"Modified by", props.LastModifiedBy},
+			{"Created", formatOfficeDate(props.Created)},
+			{"Modified", formatOfficeDate(props.Modified)},
+			{"Application", props.Application},
+		}
+
+		for _, count := range []struct {
+			name  string
+			value int
+		}{
+			{"Pages", props.Pages},
+			{"Words", props.Words},
+			{"Characters", props.Characters},
+			{"Paragraphs", props.Paragraphs},
+			{"Slides", props.Slides},
+		} {
+			if count.value > 0 {
+				fields = append(fields, [2]string{count.name, strconv.Itoa(count.value)})
+			}
+		}
+
+		var content strings.Builder
+		for _, field := range fields {
+			if field[1] != "" {
+				fmt.Fprintf(&content, "%-13s%s\n", field[0], strings.TrimSpace(field[1]))
+			}
+		}
+
+		if content.Len() == 0 {
+			return fileContentMsg{path: path, content: "The document has no properties"}
+		}
+
+		return fileContentMsg{path: path, content: content.String()}
+	}
+}
+
+// formatOfficeDate formats a W3CDTF date of a document property in the
+// local time zone, leaving dates in other formats as they are.
+func formatOfficeDate(date string) string {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(date))
+	if err != nil {
+		return date
+	}
+
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// hexDump reads the start of a binary file and formats it as a hex dump
+// fitting the given width, below a description of the file.
+func hexDump(path string, width int) tea.Cmd {
+	return func() tea.Msg {
+		data, err := filesystem.ReadPrefix(path, hexPreviewBytes)
+		if err != nil {
+			return hexContentMsg{path: path, content: err.Error()}
+		}
+
+		content := fmt.Sprintf("%s\n\n%s", describeBinaryFile(path), renderer.HexDump(data, width))
+		if info, err := os.Stat(path); err == nil && info.Size() > int64(len(data)) {
+			content = fmt.Sprintf("%s\n\nShowing the first %d bytes", content, len(data))
+		}
+
+		return hexContentMsg{path: path, content: content}
+	}
+}
+
+// isSVG returns true if the file name is that of an SVG image.
+func isSVG(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".svg")
+}
+
+// decodeImageCmd decodes an image, downscaling it to be cached. Images with
+// more than maxPixels pixels aren't decoded at all, unless maxPixels is 0.
+// SVG images are drawn at the size they are cached at.
+func decodeImageCmd(key imageKey, maxPixels int64) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Open(key.path)
+		if err != nil {
+			return imageDecodedMsg{key: key, err: err}
+		}
+		defer f.Close()
+
+		if isSVG(key.path) {
+			img, err := renderer.RasterizeSVG(f, cachedImageSide, cachedImageSide)
+
+			return imageDecodedMsg{key: key, img: img, err: err}
+		}
+
+		config, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return imageDecodedMsg{key: key, err: err}
+		}
+
+		if pixels := int64(config.Width) * int64(config.Height); maxPixels > 0 && pixels > maxPixels {
+			return imageDecodedMsg{key: key, err: fmt.Errorf(
+				"image is %d×%d pixels, more than the %d pixels max_image_pixels allows to preview",
+				config.Width, config.Height, maxPixels,
+			)}
+		}
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return imageDecodedMsg{key: key, err: err}
+		}
+
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return imageDecodedMsg{key: key, err: err}
+		}
+
+		return imageDecodedMsg{key: key, img: renderer.Downscale(img, cachedImageSide)}
+	}
+}
+
+// renderImage renders a decoded image as true color blocks.
+func renderImage(path string, img image.Image, width, height int) tea.Cmd {
+	return func() tea.Msg {
+		return fileContentMsg{path: path, content: renderer.RenderImage(img, width, height)}
+	}
+}
+
+// readPDFContent extracts the text of the first pages of a PDF, giving up
+// once pdfPreviewTimeout has passed.
+func readPDFContent(path string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pdfPreviewTimeout)
+		defer cancel()
+
+		type result struct {
+			text string
+			err  error
+		}
+
+		done := make(chan result, 1)
+		go func() {
+			text, err := filesystem.ReadPDFText(ctx, path, pdfPreviewPages)
+			done <- result{text: text, err: err}
+		}()
+
+		select {
+		case res := <-done:
+			if res.err != nil {
+				return pdfContentMsg{path: path, content: fmt.Sprintf("Binary file, no text could be extracted: %v", res.err)}
+			}
+
+			return pdfContentMsg{path: path, content: res.text}
+		case <-ctx.Done():
+			return pdfContentMsg{path: path, content: "Binary file, extracting its text took too long"}
+		}
+	}
+}
+
+// renderMarkdown renders up to maxBytes bytes of a markdown file with
+// glamour, wrapping it to the given width and picking a style matching the
+// terminal background.
+func renderMarkdown(path string, width int, maxBytes int64) tea.Cmd {
+	return func() tea.Msg {
+		content, truncated, err := readPreviewContent(path, maxBytes)
+		if err != nil {
+			return markdownContentMsg{path: path, content: err.Error()}
+		}
+
+		style := "light"
+		if lipgloss.HasDarkBackground() {
+			style = "dark"
+		}
+
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(style),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			return markdownContentMsg{path: path, content: err.Error()}
+		}
+
+		out, err := r.Render(content)
+		if err != nil {
+			return markdownContentMsg{path: path, content: err.Error()}
+		}
+
+		return markdownContentMsg{path: path, content: withTruncatedMarker(out, truncated)}
+	}
+}
+
+// renderTable renders a CSV or TSV file as a table fitting the given width,
+// noting if only its first rows are shown.
+func renderTable(path string, width int, headerColor, separatorColor lipgloss.AdaptiveColor) tea.Cmd {
+	return func() tea.Msg {
+		rows, truncated, err := filesystem.ReadTable(path, tablePreviewRows)
+		if err != nil {
+			return tableContentMsg{path: path, content: err.Error()}
+		}
+
+		content := renderer.RenderTable(rows, width, headerColor, separatorColor)
+		if truncated {
+			content = fmt.Sprintf("%s\n\nShowing the first %d rows", content, tablePreviewRows)
+		}
+
+		return tableContentMsg{path: path, content: content}
+	}
+}
+
+// readDirListing lists the entries of a directory for its preview, coloring
+// their names with the given palette and showing sizes in the given format.
+// Names are sorted ignoring case unless caseSensitive is set.
+func readDirListing(path string, palette lscolors.Palette, sizeFormat strfmt.SizeFormat, caseSensitive bool) tea.Cmd {
+	return func() tea.Msg {
+		content, err := filesystem.ListDirectory(path, palette, sizeFormat, caseSensitive)
+		if err != nil {
+			return dirListingMsg{path: path, content: fmt.Sprintf("Unable to read directory: %v", err)}
+		}
+
+		return dirListingMsg{path: path, content: content}
+	}
+}
+
+// readArchiveContent lists the entries of an archive without extracting it,
+// with sizes in the given format.
+func readArchiveContent(path string, sizeFormat strfmt.SizeFormat) tea.Cmd {
+	return func() tea.Msg {
+		content, err := filesystem.ListArchive(path, sizeFormat)
+		if err != nil {
+			return archiveContentMsg{path: path, content: err.Error()}
+		}
+
+		return archiveContentMsg{path: path, content: content}
+	}
+}
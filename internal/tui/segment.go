@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/strfmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusSegmentTimeFormat is the format the {time} token is replaced with.
+const statusSegmentTimeFormat = "15:04"
+
+// statusSegment is the rendered status segment along with the values its
+// tokens are replaced with.
+type statusSegment struct {
+	view         string
+	clockTicking bool
+	userName     string
+	hostName     string
+}
+
+type clockTickMsg struct{}
+
+// clockTickCmd ticks once the minute shown by the {time} token changes.
+func clockTickCmd() tea.Cmd {
+	now := time.Now()
+
+	return tea.Tick(now.Truncate(time.Minute).Add(time.Minute).Sub(now), func(time.Time) tea.Msg {
+		return clockTickMsg{}
+	})
+}
+
+// showsClock returns true if the status segment shows the time.
+func showsClock(cfg config.Config) bool {
+	return strings.Contains(cfg.Settings.StatusSegment, "{time}")
+}
+
+// currentUserName returns the name of the user running fm.
+func currentUserName() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+// currentHostName returns the name of the host fm runs on.
+func currentHostName() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
+// statusSegmentView renders the status_segment template with its tokens
+// replaced, leaving unknown tokens as they are. It is empty without a
+// template and takes up at most a quarter of the width.
+func (b Bubble) statusSegmentView() string {
+	template := b.config.Settings.StatusSegment
+	if template == "" {
+		return ""
+	}
+
+	text := strings.NewReplacer(
+		"{time}", time.Now().Format(statusSegmentTimeFormat),
+		"{path}", b.currentDir,
+		"{user}", b.segment.userName,
+		"{host}", b.segment.hostName,
+	).Replace(template)
+
+	style := lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(b.theme.StatusBarSegmentForegroundColor).
+		Background(b.theme.StatusBarSegmentBackgroundColor)
+
+	width := b.width/4 - style.GetHorizontalPadding()
+	if width < 1 {
+		return ""
+	}
+
+	return style.Render(strfmt.TruncateMiddle(text, width, "…"))
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"sort"
+)
+
+// toggleSelection adds the selected item to the selection or removes it if
+// it was already selected.
+func (b *Bubble) toggleSelection() {
+	path := b.filetree.GetSelectedItem().FileName()
+	if path == "" {
+		return
+	}
+
+	if _, ok := b.selection[path]; ok {
+		delete(b.selection, path)
+		return
+	}
+
+	b.selection[path] = struct{}{}
+}
+
+// selectedPaths returns the selected items, or the item under the cursor if
+// nothing is selected and it isn't the entry leading to the parent directory.
+func (b Bubble) selectedPaths() []string {
+	if len(b.selection) == 0 {
+		selectedFile := b.filetree.GetSelectedItem()
+		if selectedFile.FileName() != "" && selectedFile.ShortName() != parentDirectoryName {
+			return []string{selectedFile.FileName()}
+		}
+
+		return nil
+	}
+
+	paths := make([]string, 0, len(b.selection))
+	for path := range b.selection {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+// clearSelection removes all items from the selection.
+func (b *Bubble) clearSelection() {
+	b.selection = make(map[string]struct{})
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/strfmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// statusMessageLifetime is how long a status message is shown in the status bar.
+	statusMessageLifetime = 3 * time.Second
+
+	// maxSelectedFileWidth is the width the status bar cuts the selected file
+	// column off at.
+	maxSelectedFileWidth = 30
+
+	// minSelectedFileNameWidth is the width the name of the selected file is
+	// never shortened below, even if that leaves no room for its symlink
+	// target or git status.
+	minSelectedFileNameWidth = 8
+)
+
+type statusMessageMsg string
+
+type statusMessageTimeoutMsg struct {
+	id int
+}
+
+// statusMessage is the message shown in the status bar, with the id of the
+// timeout clearing it.
+type statusMessage struct {
+	message   string
+	messageID int
+}
+
+// emptyDirectoryText tells a directory without entries apart from one which
+// couldn't be read, returning false if the file tree lists any entries or
+// the hidden entries of the directory weren't counted yet.
+func (b Bubble) emptyDirectoryText() (string, bool) {
+	if b.filetree.TotalItems() > 0 || b.filetree.IsFiltering() {
+		return "", false
+	}
+
+	hidden, ok := b.dirs.hiddenCounts[b.currentDir]
+	if !ok {
+		return "", false
+	}
+
+	if err := b.dirs.readErrors[b.currentDir]; err != nil {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			err = pathErr.Err
+		}
+
+		return fmt.Sprintf("Unable to read directory: %v", err), true
+	}
+
+	if hidden > 0 {
+		return fmt.Sprintf("Directory is empty (%d hidden)", hidden), true
+	}
+
+	return "Directory is empty", true
+}
+
+// updateStatusbar updates the content of the statusbar.
+func (b *Bubble) updateStatusbar() {
+	logoText := "FM"
+	if glyph := b.iconSet.Directory(); glyph != "" {
+		logoText = fmt.Sprintf("%s %s", glyph, logoText)
+	}
+
+	totalText := fmt.Sprintf("%d/%d", b.filetree.Cursor(), b.filetree.TotalItems())
+	if hidden := b.dirs.hiddenCounts[b.currentDir]; hidden > 0 {
+		totalText = fmt.Sprintf("%s (%d hidden)", totalText, hidden)
+	}
+
+	if text, ok := b.emptyDirectoryText(); ok {
+		totalText = text
+	}
+
+	if b.activeBox == 1 {
+		totalText = b.previewFocusText()
+	}
+
+	summary, hasSummary := b.dirs.summaries[b.currentDir]
+	switch {
+	case b.showDirSummary && hasSummary:
+		totalText = fmt.Sprintf(
+			"%d files %d dirs %s %s",
+			summary.Files,
+			summary.Directories,
+			strfmt.FormatSize(summary.Size, b.sizeFormat),
+			totalText,
+		)
+	case b.item.sizeUnknown:
+		totalText = fmt.Sprintf("size unknown %s", totalText)
+	case b.item.size >= 0:
+		totalText = fmt.Sprintf("%s %s", strfmt.FormatSize(b.item.size, b.sizeFormat), totalText)
+	}
+
+	if b.config.Settings.ShowDiskSpace && b.dirs.diskSpace != nil {
+		totalText = fmt.Sprintf(
+			"%s free of %s %s",
+			strfmt.FormatSize(b.dirs.diskSpace.Available, b.sizeFormat),
+			strfmt.FormatSize(b.dirs.diskSpace.Total, b.sizeFormat),
+			totalText,
+		)
+	}
+
+	if len(b.selection) > 0 {
+		totalText = fmt.Sprintf("%d selected %s", len(b.selection), totalText)
+	}
+
+	if b.clipboard != nil {
+		totalText = fmt.Sprintf("%s %s %s", b.clipboard.verb(), itemsSubject(b.clipboard.paths), totalText)
+	}
+
+	var selectedFilePrefix, selectedFileSuffix string
+	if b.item.linkTarget != "" {
+		selectedFileSuffix = fmt.Sprintf(" -> %s", b.item.linkTarget)
+	}
+
+	selectedPath := filepath.Join(b.currentDir, filepath.Base(b.filetree.GetSelectedItem().FileName()))
+	if status := b.dirs.gitStatuses[b.currentDir][selectedPath]; status != "" {
+		selectedFileSuffix = fmt.Sprintf("%s [%s]", selectedFileSuffix, status)
+	}
+	if _, ok := b.selection[b.filetree.GetSelectedItem().FileName()]; ok {
+		selectedFilePrefix = "+ "
+	}
+
+	nameWidth := b.selectedFileWidth() - lipgloss.Width(selectedFilePrefix+selectedFileSuffix)
+	if nameWidth < minSelectedFileNameWidth {
+		nameWidth = minSelectedFileNameWidth
+	}
+
+	selectedFileText := selectedFilePrefix +
+		strfmt.TruncateMiddle(b.filetree.GetSelectedItem().ShortName(), nameWidth, "…") +
+		selectedFileSuffix
+
+	statusText := b.filetree.GetSelectedItem().CurrentDirectory()
+	if b.operation != nil {
+		statusText = b.operation.progressView(b.sizeFormat)
+	}
+
+	if b.status.message != "" {
+		statusText = b.status.message
+	}
+
+	if b.prompt.mode != noInputMode {
+		statusText = b.prompt.input.View()
+	}
+
+	if b.prompt.renameCollision {
+		statusText += lipgloss.NewStyle().Foreground(b.theme.WarningColor).Render("  already exists")
+	}
+
+	b.segment.view = b.statusSegmentView()
+	b.statusbar.SetSize(b.width - lipgloss.Width(b.segment.view))
+	b.statusbar.SetContent(
+		selectedFileText,
+		statusText,
+		totalText,
+		logoText,
+	)
+}
+
+// selectedFileWidth returns the width of the selected file column of the
+// status bar, a share of the terminal width up to what the status bar shows.
+func (b Bubble) selectedFileWidth() int {
+	width := b.width / 5
+	if width > maxSelectedFileWidth {
+		return maxSelectedFileWidth
+	}
+
+	return width
+}
+
+// previewFocusText returns the text shown in place of the cursor position
+// while the right box is focused, including how far a text preview is scrolled.
+func (b Bubble) previewFocusText() string {
+	if b.state == showTextState {
+		return fmt.Sprintf("preview %d%%", int(b.renderer.ScrollPercent()*100))
+	}
+
+	return "preview"
+}
+
+// setStatusMessage shows a message in the status bar for a short period of time.
+func (b *Bubble) setStatusMessage(message string) tea.Cmd {
+	logger.Info("status message", "message", message)
+
+	b.status.message = message
+	b.status.messageID++
+
+	return clearStatusMessageAfter(b.status.messageID)
+}
+
+// clearStatusMessageAfter clears the status message with the given id once its lifetime is over.
+func clearStatusMessageAfter(id int) tea.Cmd {
+	return tea.Tick(statusMessageLifetime, func(time.Time) tea.Msg {
+		return statusMessageTimeoutMsg{id: id}
+	})
+}
+
+// toggleDirSummary shows or hides the summary of the current directory,
+// summarizing directories again once it is shown.
+func (b *Bubble) toggleDirSummary() {
+	b.showDirSummary = !b.showDirSummary
+	b.dirs.summaries = make(map[string]filesystem.Summary)
+}
+
+// toggleExactSizes switches between human readable sizes and exact byte counts.
+func (b *Bubble) toggleExactSizes() {
+	if b.sizeFormat == strfmt.ExactBytes {
+		b.sizeFormat = strfmt.HumanReadable
+		return
+	}
+
+	b.sizeFormat = strfmt.ExactBytes
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tabBarHeight is the height of the tab bar shown when more than one tab is open.
+const tabBarHeight = 1
+
+// tab represents the state of a directory tab.
+type tab struct {
+	dir      string
+	selected string
+}
+
+// tabList represents the open tabs and the one shown.
+type tabList struct {
+	list   []tab
+	active int
+}
+
+// saveTab stores the state of the filetree in the active tab.
+func (b *Bubble) saveTab() {
+	b.tabs.list[b.tabs.active] = tab{
+		dir:      b.currentDirectory(),
+		selected: b.filetree.GetSelectedItem().ShortName(),
+	}
+}
+
+// restoreTab lists the directory of the active tab and selects the item
+// that was selected when leaving it.
+func (b *Bubble) restoreTab() tea.Cmd {
+	activeTab := b.tabs.list[b.tabs.active]
+	b.pendingSelection = pendingSelection{dir: activeTab.dir, name: activeTab.selected}
+
+	return b.navigateTo(activeTab.dir)
+}
+
+// newTab opens a new tab in the current directory.
+func (b *Bubble) newTab() []tea.Cmd {
+	b.saveTab()
+	b.tabs.list = append(b.tabs.list, b.tabs.list[b.tabs.active])
+	b.tabs.active = len(b.tabs.list) - 1
+
+	return b.resize()
+}
+
+// closeTab closes the active tab, quitting if it is the last one and
+// quit_on_last_tab_close is enabled.
+func (b *Bubble) closeTab() []tea.Cmd {
+	if len(b.tabs.list) == 1 {
+		if b.config.Settings.QuitOnLastTabClose {
+			return []tea.Cmd{b.quit()}
+		}
+
+		return []tea.Cmd{b.setStatusMessage("Cannot close the last tab")}
+	}
+
+	b.tabs.list = append(b.tabs.list[:b.tabs.active], b.tabs.list[b.tabs.active+1:]...)
+	if b.tabs.active >= len(b.tabs.list) {
+		b.tabs.active = len(b.tabs.list) - 1
+	}
+
+	return append(b.resize(), b.restoreTab())
+}
+
+// switchTab activates the tab at the given index, wrapping around at both ends.
+func (b *Bubble) switchTab(index int) tea.Cmd {
+	if len(b.tabs.list) == 1 {
+		return nil
+	}
+
+	b.saveTab()
+	b.tabs.active = (index + len(b.tabs.list)) % len(b.tabs.list)
+
+	return b.restoreTab()
+}
+
+// tabBarView returns the tab bar with the active tab highlighted.
+func (b Bubble) tabBarView() string {
+	tabStyle := lipgloss.NewStyle().Padding(0, 1)
+	activeTabStyle := tabStyle.Copy().
+		Bold(true).
+		Background(b.theme.TitleBackgroundColor).
+		Foreground(b.theme.TitleForegroundColor)
+
+	tabs := make([]string, 0, len(b.tabs.list))
+	for i, t := range b.tabs.list {
+		name := filepath.Base(t.dir)
+		if i == b.tabs.active {
+			name = filepath.Base(b.currentDirectory())
+			tabs = append(tabs, activeTabStyle.Render(name))
+
+			continue
+		}
+
+		tabs = append(tabs, tabStyle.Render(name))
+	}
+
+	return lipgloss.NewStyle().
+		MaxWidth(b.width).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+}
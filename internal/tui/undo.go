@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/knipferrc/fm/internal/filesystem"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is the number of file operations which can be undone.
+const maxUndo = 20
+
+// undoAction reverts a file operation, returning the path to select afterwards.
+type undoAction struct {
+	description string
+	revert      func() (string, error)
+}
+
+// trashedItem represents an item moved to the trash.
+type trashedItem struct {
+	trashPath    string
+	originalPath string
+}
+
+// renameUndo returns the action renaming an item back to its old path.
+func renameUndo(oldPath, newPath string) *undoAction {
+	return &undoAction{
+		description: fmt.Sprintf("rename of %s", filepath.Base(oldPath)),
+		revert: func() (string, error) {
+			return oldPath, filesystem.Rename(newPath, oldPath)
+		},
+	}
+}
+
+// moveUndo returns the action moving items back to where they were moved from.
+func moveUndo(moves []transfer) *undoAction {
+	srcs := make([]string, 0, len(moves))
+	for _, move := range moves {
+		srcs = append(srcs, move.src)
+	}
+
+	return &undoAction{
+		description: fmt.Sprintf("move of %s", itemsSubject(srcs)),
+		revert: func() (string, error) {
+			for i := len(moves) - 1; i >= 0; i-- {
+				if err := filesystem.Move(context.Background(), moves[i].dst, moves[i].src, func(int64) {}); err != nil {
+					return "", err
+				}
+			}
+
+			return moves[0].src, nil
+		},
+	}
+}
+
+// bulkRenameUndo returns the action renaming items back to their old paths.
+func bulkRenameUndo(pairs []filesystem.RenamePair) *undoAction {
+	reverted := make([]filesystem.RenamePair, len(pairs))
+	for i, pair := range pairs {
+		reverted[i] = filesystem.RenamePair{Src: pair.Dst, Dst: pair.Src}
+	}
+
+	return &undoAction{
+		description: fmt.Sprintf("rename of %d items", len(pairs)),
+		revert: func() (string, error) {
+			return pairs[0].Src, filesystem.RenameAll(reverted)
+		},
+	}
+}
+
+// trashUndo returns the action restoring items from the trash.
+func trashUndo(items []trashedItem) *undoAction {
+	subject := filepath.Base(items[0].originalPath)
+	if len(items) > 1 {
+		subject = fmt.Sprintf("%d items", len(items))
+	}
+
+	return &undoAction{
+		description: fmt.Sprintf("deletion of %s", subject),
+		revert: func() (string, error) {
+			for _, item := range items {
+				if err := filesystem.RestoreFromTrash(item.trashPath, item.originalPath); err != nil {
+					return "", err
+				}
+			}
+
+			return items[0].originalPath, nil
+		},
+	}
+}
+
+// pushUndo records a file operation which can be undone, dropping the
+// oldest one once maxUndo operations are recorded.
+func (b *Bubble) pushUndo(action *undoAction) {
+	b.undoStack = append(b.undoStack, action)
+	if len(b.undoStack) > maxUndo {
+		b.undoStack = b.undoStack[len(b.undoStack)-maxUndo:]
+	}
+}
+
+// undo reverts the last recorded file operation.
+func (b *Bubble) undo() tea.Cmd {
+	if len(b.undoStack) == 0 {
+		return b.setStatusMessage("Nothing to undo")
+	}
+
+	action := b.undoStack[len(b.undoStack)-1]
+	b.undoStack = b.undoStack[:len(b.undoStack)-1]
+
+	return undoCmd(action)
+}
+
+// undoCmd reverts a file operation.
+func undoCmd(action *undoAction) tea.Cmd {
+	return func() tea.Msg {
+		selectPath, err := action.revert()
+		if err != nil {
+			return fileOperationMsg{message: fmt.Sprintf("Unable to undo the %s: %v", action.description, err)}
+		}
+
+		return fileOperationMsg{
+			message:    fmt.Sprintf("Undid the %s", action.description),
+			selectPath: selectPath,
+		}
+	}
+}
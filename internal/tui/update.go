@@ -1,244 +1,86 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"os/exec"
+	"path/filepath"
 
-	"github.com/knipferrc/fm/internal/config"
-	"github.com/knipferrc/fm/internal/theme"
+	"github.com/knipferrc/fm/internal/filesystem"
+	"github.com/knipferrc/fm/internal/logger"
+	"github.com/knipferrc/fm/internal/picker"
+	"github.com/knipferrc/fm/internal/templates"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/knipferrc/teacup/help"
-	"github.com/knipferrc/teacup/icons"
-	"github.com/knipferrc/teacup/statusbar"
 )
 
-var forbiddenExtensions = []string{
-	".FCStd",
-	".gif",
-	".zip",
-	".rar",
-	".webm",
-	".sqlite",
-	".sqlite-shm",
-	".sqlite-wal",
-	".DS_Store",
-	".db",
-	".data",
-	".plist",
-	".webp",
-	".img",
-}
-
-// resetViewports goes to the top of all bubbles viewports.
-func (b *Bubble) resetViewports() {
-	b.code.GotoTop()
-	b.pdf.GotoTop()
-	b.markdown.GotoTop()
-	b.help.GotoTop()
-	b.image.GotoTop()
-}
-
-// deactivateALlBubbles sets all bubbles to inactive.
-func (b *Bubble) deactivateAllBubbles() {
-	b.filetree.SetIsActive(false)
-	b.code.SetIsActive(false)
-	b.markdown.SetIsActive(false)
-	b.image.SetIsActive(false)
-	b.pdf.SetIsActive(false)
-	b.help.SetIsActive(false)
-}
-
-// resetBorderColors resets all bubble border colors to default.
-func (b *Bubble) resetBorderColors() {
-	b.filetree.SetBorderColor(b.theme.InactiveBoxBorderColor)
-	b.help.SetBorderColor(b.theme.InactiveBoxBorderColor)
-	b.code.SetBorderColor(b.theme.InactiveBoxBorderColor)
-	b.image.SetBorderColor(b.theme.InactiveBoxBorderColor)
-	b.markdown.SetBorderColor(b.theme.InactiveBoxBorderColor)
-	b.pdf.SetBorderColor(b.theme.InactiveBoxBorderColor)
-}
-
-// reloadConfig reloads the config file and updates the UI.
-func (b *Bubble) reloadConfig() []tea.Cmd {
-	var cmds []tea.Cmd
-
-	cfg, err := config.ParseConfig()
-	if err != nil {
-		return nil
+// ownsKey returns true if a key is handled by fm itself rather than the filetree.
+func (b Bubble) ownsKey(msg tea.KeyMsg) bool {
+	if b.modal.Visible() || b.keyHelp.Visible() || b.finder.Visible() || b.prompt.mode != noInputMode {
+		return true
 	}
 
-	b.config = cfg
-	syntaxTheme := cfg.Theme.SyntaxTheme.Light
-	if lipgloss.HasDarkBackground() {
-		syntaxTheme = cfg.Theme.SyntaxTheme.Dark
+	if (b.operation != nil || b.clipboard != nil) && key.Matches(msg, b.keys.Cancel) {
+		return true
 	}
 
-	b.code.SetSyntaxTheme(syntaxTheme)
-
-	theme := theme.GetTheme(cfg.Theme.AppTheme)
-	b.theme = theme
-	b.statusbar.SetColors(
-		statusbar.ColorConfig{
-			Foreground: theme.StatusBarSelectedFileForegroundColor,
-			Background: theme.StatusBarSelectedFileBackgroundColor,
-		},
-		statusbar.ColorConfig{
-			Foreground: theme.StatusBarBarForegroundColor,
-			Background: theme.StatusBarBarBackgroundColor,
-		},
-		statusbar.ColorConfig{
-			Foreground: theme.StatusBarTotalFilesForegroundColor,
-			Background: theme.StatusBarTotalFilesBackgroundColor,
-		},
-		statusbar.ColorConfig{
-			Foreground: theme.StatusBarLogoForegroundColor,
-			Background: theme.StatusBarLogoBackgroundColor,
-		},
-	)
-
-	b.help.SetTitleColor(
-		help.TitleColor{
-			Background: theme.TitleBackgroundColor,
-			Foreground: theme.TitleForegroundColor,
-		},
-	)
-
-	b.filetree.SetTitleColors(theme.TitleForegroundColor, theme.TitleBackgroundColor)
-	b.filetree.SetSelectedItemColors(theme.SelectedTreeItemColor)
-	cmds = append(cmds, b.filetree.ToggleShowIcons(cfg.Settings.ShowIcons))
-
-	b.filetree.SetBorderless(cfg.Settings.Borderless)
-	b.code.SetBorderless(cfg.Settings.Borderless)
-	b.help.SetBorderless(cfg.Settings.Borderless)
-	b.markdown.SetBorderless(cfg.Settings.Borderless)
-	b.pdf.SetBorderless(cfg.Settings.Borderless)
-	b.image.SetBorderless(cfg.Settings.Borderless)
-
-	if b.activeBox == 0 {
-		b.deactivateAllBubbles()
-		b.filetree.SetIsActive(true)
-		b.resetBorderColors()
-		b.filetree.SetBorderColor(theme.ActiveBoxBorderColor)
-	} else {
-		switch b.state {
-		case idleState:
-			b.deactivateAllBubbles()
-			b.help.SetIsActive(true)
-			b.resetBorderColors()
-			b.help.SetBorderColor(theme.ActiveBoxBorderColor)
-		case showCodeState:
-			b.deactivateAllBubbles()
-			b.code.SetIsActive(true)
-			b.resetBorderColors()
-			b.code.SetBorderColor(theme.ActiveBoxBorderColor)
-		case showImageState:
-			b.deactivateAllBubbles()
-			b.image.SetIsActive(true)
-			b.resetBorderColors()
-			b.image.SetBorderColor(theme.ActiveBoxBorderColor)
-		case showMarkdownState:
-			b.deactivateAllBubbles()
-			b.markdown.SetIsActive(true)
-			b.resetBorderColors()
-			b.markdown.SetBorderColor(theme.ActiveBoxBorderColor)
-		case showPdfState:
-			b.deactivateAllBubbles()
-			b.markdown.SetIsActive(true)
-			b.resetBorderColors()
-			b.pdf.SetBorderColor(theme.ActiveBoxBorderColor)
-		}
+	if b.state == showPickerState && b.activeBox == 1 {
+		return true
 	}
 
-	return cmds
-}
-
-// openFile opens the currently selected file.
-func (b *Bubble) openFile() []tea.Cmd {
-	var cmds []tea.Cmd
-
-	selectedFile := b.filetree.GetSelectedItem()
-	if !selectedFile.IsDirectory() {
-		b.resetViewports()
-
-		switch {
-		case selectedFile.FileExtension() == ".png" || selectedFile.FileExtension() == ".jpg" || selectedFile.FileExtension() == ".jpeg":
-			b.state = showImageState
-			readFileCmd := b.image.SetFileName(selectedFile.FileName())
-			cmds = append(cmds, readFileCmd)
-		case selectedFile.FileExtension() == ".md" && b.config.Settings.PrettyMarkdown:
-			b.state = showMarkdownState
-			markdownCmd := b.markdown.SetFileName(selectedFile.FileName())
-			cmds = append(cmds, markdownCmd)
-		case selectedFile.FileExtension() == ".pdf":
-			b.state = showPdfState
-			pdfCmd := b.pdf.SetFileName(selectedFile.FileName())
-			cmds = append(cmds, pdfCmd)
-		case contains(forbiddenExtensions, selectedFile.FileExtension()):
-			return nil
-		default:
-			b.state = showCodeState
-			readFileCmd := b.code.SetFileName(selectedFile.FileName())
-			cmds = append(cmds, readFileCmd)
-		}
+	if b.filetree.IsFiltering() {
+		return false
 	}
 
-	return cmds
-}
-
-// toggleBox toggles between the two boxes.
-func (b *Bubble) toggleBox() {
-	b.activeBox = (b.activeBox + 1) % 2
-	if b.activeBox == 0 {
-		b.deactivateAllBubbles()
-		b.filetree.SetIsActive(true)
-		b.resetBorderColors()
-		b.filetree.SetBorderColor(b.theme.ActiveBoxBorderColor)
-	} else {
-		switch b.state {
-		case idleState:
-			b.deactivateAllBubbles()
-			b.help.SetIsActive(true)
-			b.resetBorderColors()
-			b.help.SetBorderColor(b.theme.ActiveBoxBorderColor)
-		case showCodeState:
-			b.deactivateAllBubbles()
-			b.code.SetIsActive(true)
-			b.resetBorderColors()
-			b.code.SetBorderColor(b.theme.ActiveBoxBorderColor)
-		case showImageState:
-			b.deactivateAllBubbles()
-			b.image.SetIsActive(true)
-			b.resetBorderColors()
-			b.image.SetBorderColor(b.theme.ActiveBoxBorderColor)
-		case showMarkdownState:
-			b.deactivateAllBubbles()
-			b.markdown.SetIsActive(true)
-			b.resetBorderColors()
-			b.markdown.SetBorderColor(b.theme.ActiveBoxBorderColor)
-		case showPdfState:
-			b.deactivateAllBubbles()
-			b.markdown.SetIsActive(true)
-			b.resetBorderColors()
-			b.pdf.SetBorderColor(b.theme.ActiveBoxBorderColor)
-		}
+	if b.ownsCountKey(msg) || contains(filetreeActionKeys, msg.String()) {
+		return true
 	}
-}
 
-// updateStatusbar updates the content of the statusbar.
-func (b *Bubble) updateStatusbar() {
-	logoText := fmt.Sprintf("%s %s", icons.IconDef["dir"].GetGlyph(), "FM")
-	if !b.config.Settings.ShowIcons {
-		logoText = "FM"
+	if key.Matches(msg, b.keys.Submit) && (b.selectedArchive() != "" || b.selectedLinkedDir() != "") {
+		return true
 	}
 
-	b.statusbar.SetContent(
-		b.filetree.GetSelectedItem().ShortName(),
-		b.filetree.GetSelectedItem().CurrentDirectory(),
-		fmt.Sprintf("%d/%d", b.filetree.Cursor(), b.filetree.TotalItems()),
-		logoText,
-	)
+	return key.Matches(msg, b.keys.CopyPath) ||
+		key.Matches(msg, b.keys.CopyContents) ||
+		key.Matches(msg, b.keys.Delete) ||
+		key.Matches(msg, b.keys.Select) ||
+		key.Matches(msg, b.keys.AddBookmark) ||
+		key.Matches(msg, b.keys.ShowBookmarks) ||
+		key.Matches(msg, b.keys.ShowMounts) ||
+		key.Matches(msg, b.keys.NewTab) ||
+		key.Matches(msg, b.keys.CloseTab) ||
+		key.Matches(msg, b.keys.NextTab) ||
+		key.Matches(msg, b.keys.PreviousTab) ||
+		key.Matches(msg, b.keys.Edit) ||
+		key.Matches(msg, b.keys.CreateFile) ||
+		key.Matches(msg, b.keys.NewFromTemplate) ||
+		key.Matches(msg, b.keys.OpenShell) ||
+		key.Matches(msg, b.keys.FromClipboard) ||
+		(key.Matches(msg, b.keys.GoToLine) && b.activeBox == 1) ||
+		key.Matches(msg, b.keys.CreateDirectory) ||
+		key.Matches(msg, b.keys.Copy) ||
+		key.Matches(msg, b.keys.GoToPath) ||
+		key.Matches(msg, b.keys.GoToStartDir) ||
+		key.Matches(msg, b.keys.Duplicate) ||
+		key.Matches(msg, b.keys.CycleTheme) ||
+		key.Matches(msg, b.keys.Find) ||
+		key.Matches(msg, b.keys.ShrinkTree) ||
+		key.Matches(msg, b.keys.GrowTree) ||
+		key.Matches(msg, b.keys.TogglePreview) ||
+		key.Matches(msg, b.keys.ToggleSummary) ||
+		key.Matches(msg, b.keys.OpenWith) ||
+		(key.Matches(msg, b.keys.HalfPageDown, b.keys.HalfPageUp, b.keys.PageDown, b.keys.PageUp) && b.activeBox == 0) ||
+		key.Matches(msg, b.keys.Rename) ||
+		key.Matches(msg, b.keys.Move) ||
+		key.Matches(msg, b.keys.SaveAs) ||
+		key.Matches(msg, b.keys.BulkRename) ||
+		key.Matches(msg, b.keys.ToggleSizes) ||
+		key.Matches(msg, b.keys.ParentDirectory) ||
+		key.Matches(msg, b.keys.Help) ||
+		key.Matches(msg, b.keys.Undo) ||
+		key.Matches(msg, b.keys.Cut) ||
+		key.Matches(msg, b.keys.Paste)
 }
 
 // contains returns true if the slice contains the string.
@@ -259,56 +101,538 @@ func (b Bubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds []tea.Cmd
 	)
 
-	b.filetree, cmd = b.filetree.Update(msg)
-	cmds = append(cmds, cmd)
+	if result, ok := msg.(previewResultMsg); ok {
+		if result.gen != b.preview.gen || result.msg == nil {
+			if decoded, ok := result.msg.(imageDecodedMsg); ok && decoded.err == nil {
+				b.preview.images.add(decoded.key, decoded.img)
+			}
+
+			return b, nil
+		}
+
+		msg = result.msg
+	}
+
+	// Keys handled by fm aren't passed on to the filetree or the preview, so
+	// that typing into an input doesn't also scroll the focused preview.
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	ownsKey := isKey && b.ownsKey(keyMsg)
+	if !ownsKey {
+		b.filetree, cmd = b.filetree.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if !isKey {
+		cmds = append(cmds, b.applyPendingSelection())
+	}
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		resizeImgCmd := b.image.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		markdownCmd := b.markdown.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		b.filetree.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		b.help.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		b.code.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		b.pdf.SetSize(msg.Width/2, msg.Height-statusbar.Height)
-		b.statusbar.SetSize(msg.Width)
-
-		cmds = append(cmds, b.filetree.ToggleShowIcons(b.config.Settings.ShowIcons))
-		cmds = append(cmds, resizeImgCmd, markdownCmd)
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, b.keys.Quit):
+		b.width = msg.Width
+		b.height = msg.Height
+
+		cmds = append(cmds, b.resize()...)
+		cmds = append(cmds, b.filetree.ToggleShowIcons(b.iconSet.TreeIcons()))
+	case tea.MouseMsg:
+		cmds = append(cmds, b.handleMouse(msg))
+	case symlinkMsg:
+		if msg.path == b.item.path {
+			b.item.linkTarget = msg.target
+			if msg.resolved == "" {
+				b.item.linkTarget = fmt.Sprintf("%s (broken)", msg.target)
+			} else if msg.isDir {
+				b.item.linkDir = msg.resolved
+			}
+		}
+	case dirSizeMsg:
+		if msg.path == b.item.path {
+			b.item.size = msg.size
+			b.item.sizeUnknown = msg.err != nil
+			b.item.cancelSize = nil
+		}
+	case configChangedMsg:
+		cmds = append(cmds, b.reloadConfig()...)
+		cmds = append(cmds, waitForConfigChangeCmd(b.configWatch.watcher, b.configWatch.path))
+	case dirChangedMsg:
+		b.dirs.forget(msg.dir)
+		delete(b.dirCursors, msg.dir)
+		cmds = append(cmds, b.handleDirChange(msg))
+	case diskSpaceMsg:
+		if msg.dir == b.dirs.diskSpaceDir {
+			b.dirs.diskSpace = nil
+			if msg.err == nil {
+				b.dirs.diskSpace = &msg.space
+			} else if !errors.Is(msg.err, filesystem.ErrDiskSpaceUnsupported) {
+				logger.Error("reading the disk space failed", "dir", msg.dir, "err", msg.err)
+			}
+		}
+	case hiddenCountMsg:
+		b.dirs.hiddenCounts[msg.dir] = msg.count
+		b.dirs.readErrors[msg.dir] = msg.err
+		if msg.dir == b.dirs.hiddenCountPath {
+			b.dirs.hiddenCountPath = ""
+		}
+	case gitStatusMsg:
+		b.dirs.gitStatuses[msg.dir] = msg.status
+		if msg.dir == b.dirs.gitStatusPath {
+			b.dirs.gitStatusPath = ""
+		}
+	case dirSummaryMsg:
+		b.dirs.summaries[msg.path] = msg.summary
+		if msg.path == b.dirs.summaryPath {
+			b.dirs.summaryPath = ""
+		}
+	case statusMessageMsg:
+		cmds = append(cmds, b.setStatusMessage(string(msg)))
+	case copyProgressMsg:
+		if b.operation != nil {
+			b.operation.done = msg.done
+			b.operation.total = msg.total
+			b.operation.tallied = true
+			cmds = append(cmds, waitForOperationCmd(b.operation.ch))
+		}
+	case operationFinishedMsg:
+		b.operation = nil
+		if b.quitting {
 			return b, tea.Quit
-		case key.Matches(msg, b.keys.Exit):
-			if !b.filetree.IsFiltering() {
-				return b, tea.Quit
+		}
+
+		result := msg.result
+		cmds = append(cmds, func() tea.Msg {
+			return result
+		})
+	case editorFinishedMsg:
+		cmds = append(cmds, b.refreshAfterExec())
+		if msg.err != nil {
+			cmds = append(cmds, b.setStatusMessage(fmt.Sprintf("Editor exited with an error: %v", msg.err)))
+		}
+	case shellFinishedMsg:
+		cmds = append(cmds, b.refreshAfterExec())
+
+		// The exit status of a shell is that of the last command run in it,
+		// so only failing to start it is reported.
+		var exitErr *exec.ExitError
+		if msg.err != nil && !errors.As(msg.err, &exitErr) {
+			cmds = append(cmds, b.setStatusMessage(fmt.Sprintf("Unable to open a shell: %v", msg.err)))
+		}
+	case bookmarksMsg:
+		items := make([]picker.Item, 0, len(msg))
+		for _, bookmark := range msg {
+			items = append(items, picker.Item{
+				Title: fmt.Sprintf("%s (%s)", bookmark.Name, bookmark.Path),
+				Value: bookmark.Path,
+			})
+		}
+
+		cmds = append(cmds, b.setPreviewVisible(true)...)
+		b.openPicker(bookmarksPicker, "Bookmarks", items)
+	case mountsMsg:
+		items := make([]picker.Item, 0, len(msg))
+		for _, mount := range msg {
+			items = append(items, picker.Item{
+				Title: withGlyph(b.iconSet.Directory(), fmt.Sprintf("%s (%s, %s)", mount.Path, mount.Type, mount.Device)),
+				Value: mount.Path,
+			})
+		}
+
+		cmds = append(cmds, b.setPreviewVisible(true)...)
+		b.openPicker(mountsPicker, "Mounts", items)
+	case clipboardTextMsg:
+		b.prompt.clipboardText = string(msg)
+		b.startInput(clipboardInputMode, "New file from clipboard: ", "")
+	case templatesMsg:
+		if len(msg) == 0 {
+			dir, _ := templates.Dir()
+			cmds = append(cmds, b.setStatusMessage(fmt.Sprintf("No templates in %s", dir)))
+			break
+		}
+
+		items := make([]picker.Item, 0, len(msg))
+		for _, template := range msg {
+			items = append(items, picker.Item{Title: template.Name, Value: template.Path})
+		}
+
+		cmds = append(cmds, b.setPreviewVisible(true)...)
+		b.openPicker(templatesPicker, "Templates", items)
+	case fileOperationMsg:
+		if msg.undo != nil {
+			b.pushUndo(msg.undo)
+		}
+
+		b.dirs.summaries = make(map[string]filesystem.Summary)
+		b.dirs.forgetEntries()
+		if msg.selectPath != "" {
+			b.pendingSelection = pendingSelection{
+				dir:  filepath.Dir(msg.selectPath),
+				name: filepath.Base(msg.selectPath),
 			}
-		case key.Matches(msg, b.keys.ReloadConfig):
-			if !b.filetree.IsFiltering() {
-				cmds = append(cmds, tea.Batch(b.reloadConfig()...))
+		}
+
+		cmds = append(cmds, b.refreshFiletree(), b.setStatusMessage(msg.message))
+	case clockTickMsg:
+		b.segment.clockTicking = showsClock(b.config)
+		if b.segment.clockTicking {
+			cmds = append(cmds, clockTickCmd())
+		}
+	case statusMessageTimeoutMsg:
+		if msg.id == b.status.messageID {
+			b.status.message = ""
+		}
+	case fileContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case archiveContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case dirListingMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case autoPreviewMsg:
+		if msg.seq == b.preview.autoSeq {
+			cmds = append(cmds, b.autoPreview()...)
+		}
+	case archiveDirMsg:
+		cmds = append(cmds, b.showArchiveDir(msg)...)
+	case markdownContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case imageDecodedMsg:
+		cmds = append(cmds, b.imageDecoded(msg)...)
+	case imageMetaMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case hexContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case tableContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case codeContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case previewCommandMsg:
+		switch {
+		case msg.path != b.preview.path:
+		case msg.err != nil:
+			cmds = append(cmds, b.setStatusMessage(fmt.Sprintf("Preview command failed: %v", msg.err)))
+			cmds = append(cmds, b.previewFile(msg.path, filepath.Ext(msg.path))...)
+		default:
+			b.renderer.SetContent(msg.content)
+		}
+	case finderPathsMsg:
+		if b.fileIndex != nil && msg.ch == b.fileIndex.ch {
+			b.finder.AddPaths(msg.paths, msg.done)
+			if !msg.done {
+				cmds = append(cmds, waitForFinderCmd(msg.ch))
 			}
-		case key.Matches(msg, b.keys.OpenFile):
-			cmds = append(cmds, tea.Batch(b.openFile()...))
-		case key.Matches(msg, b.keys.ToggleBox):
-			b.toggleBox()
 		}
+	case pdfContentMsg:
+		if msg.path == b.preview.path {
+			b.renderer.SetContent(msg.content)
+		}
+	case tea.KeyMsg:
+		cmds = append(cmds, b.handleKey(msg))
 	}
 
-	b.updateStatusbar()
+	if dir := b.filetree.GetSelectedItem().CurrentDirectory(); dir != b.currentDir {
+		b.currentDir = dir
+		if !b.config.Settings.StickySelection {
+			b.clearSelection()
+		}
+
+		if dir != b.pendingSelection.dir {
+			b.pendingSelection = pendingSelection{}
+			b.restoreCursor(dir)
+		}
+	}
+
+	if b.currentDir != "" {
+		b.dirCursors[b.currentDir] = b.filetree.GetSelectedItem().ShortName()
+	}
 
-	b.code, cmd = b.code.Update(msg)
-	cmds = append(cmds, cmd)
+	cmds = append(
+		cmds,
+		b.updateItemSize(),
+		b.updateAutoPreview(),
+		b.updateDirSummary(),
+		b.updateGitStatus(),
+		b.updateHiddenCount(),
+		b.updateDiskSpace(),
+		b.updateWatcher(),
+	)
 
-	b.markdown, cmd = b.markdown.Update(msg)
-	cmds = append(cmds, cmd)
+	if !ownsKey {
+		b.code, cmd = b.code.Update(msg)
+		cmds = append(cmds, cmd)
 
-	b.image, cmd = b.image.Update(msg)
-	cmds = append(cmds, cmd)
+		b.image, cmd = b.image.Update(msg)
+		cmds = append(cmds, cmd)
 
-	b.pdf, cmd = b.pdf.Update(msg)
-	cmds = append(cmds, cmd)
+		b.help, cmd = b.help.Update(msg)
+		cmds = append(cmds, cmd)
 
-	b.help, cmd = b.help.Update(msg)
-	cmds = append(cmds, cmd)
+		b.renderer, cmd = b.renderer.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	b.updateStatusbar()
 
 	return b, tea.Batch(cmds...)
 }
+
+// handleKey passes a key to the modal, overlay or input shown, if any, or
+// runs the action bound to it.
+func (b *Bubble) handleKey(msg tea.KeyMsg) tea.Cmd {
+	logger.Debug("key pressed", "key", msg.String())
+
+	if b.modal.Visible() {
+		return b.handleConfirmKey(msg)
+	}
+
+	if b.finder.Visible() {
+		return b.handleFinderKey(msg)
+	}
+
+	if b.keyHelp.Visible() {
+		return b.handleHelpKey(msg)
+	}
+
+	if b.prompt.mode != noInputMode {
+		return b.handleInputKey(msg)
+	}
+
+	if b.operation != nil && key.Matches(msg, b.keys.Cancel) {
+		b.operation.cancel()
+		return nil
+	}
+
+	if b.clipboard != nil && key.Matches(msg, b.keys.Cancel) {
+		b.clipboard = nil
+		return nil
+	}
+
+	if b.state == showPickerState && b.activeBox == 1 {
+		if cmd, handled := b.handlePickerKey(msg); handled {
+			return cmd
+		}
+	}
+
+	if !b.filetree.IsFiltering() && b.handleCountKey(msg) {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+
+	switch {
+	case key.Matches(msg, b.keys.Quit):
+		cmds = append(cmds, b.quit())
+	case key.Matches(msg, b.keys.Exit):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.quit())
+		}
+	case key.Matches(msg, b.keys.ReloadConfig):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, tea.Batch(b.reloadConfig()...))
+		}
+	case key.Matches(msg, b.keys.OpenFile):
+		if !b.filetree.IsFiltering() && b.filetree.GetSelectedItem().ShortName() == parentDirectoryName {
+			b.selectCurrentDirInParent()
+		}
+
+		cmds = append(cmds, tea.Batch(b.openFile()...))
+	case key.Matches(msg, b.keys.ParentDirectory):
+		switch {
+		case b.archiveEntryPreviewed():
+			cmds = append(cmds, listArchiveDirCmd(b.archive.path, b.archive.dir, b.config.Settings.CaseSensitiveSort))
+		case !b.filetree.IsFiltering():
+			cmds = append(cmds, b.goToParent())
+		}
+	case key.Matches(msg, b.keys.Submit):
+		if path := b.selectedArchive(); path != "" {
+			cmds = append(cmds, b.openArchive(path))
+		} else if dir := b.selectedLinkedDir(); dir != "" {
+			cmds = append(cmds, b.navigateTo(dir))
+		}
+	case key.Matches(msg, b.keys.Help):
+		if !b.filetree.IsFiltering() {
+			b.keyHelp.Show()
+		}
+	case key.Matches(msg, b.keys.ToggleBox):
+		b.toggleBox()
+	case key.Matches(msg, b.keys.TogglePreview):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.setPreviewVisible(!b.showPreview)...)
+		}
+	case key.Matches(msg, b.keys.ToggleSummary):
+		if !b.filetree.IsFiltering() {
+			b.toggleDirSummary()
+		}
+	case key.Matches(msg, b.keys.HalfPageDown):
+		if b.activeBox == 0 && !b.filetree.IsFiltering() {
+			b.moveCursor(b.filetreePageSize() / 2)
+		}
+	case key.Matches(msg, b.keys.HalfPageUp):
+		if b.activeBox == 0 && !b.filetree.IsFiltering() {
+			b.moveCursor(-b.filetreePageSize() / 2)
+		}
+	case key.Matches(msg, b.keys.PageDown):
+		if b.activeBox == 0 && !b.filetree.IsFiltering() {
+			b.moveCursor(b.filetreePageSize())
+		}
+	case key.Matches(msg, b.keys.PageUp):
+		if b.activeBox == 0 && !b.filetree.IsFiltering() {
+			b.moveCursor(-b.filetreePageSize())
+		}
+	case key.Matches(msg, b.keys.OpenWith):
+		if !b.filetree.IsFiltering() && b.filetree.GetSelectedItem().FileName() != "" {
+			cmds = append(cmds, openWithDefaultAppCmd(b.filetree.GetSelectedItem().FileName()))
+		}
+	case key.Matches(msg, b.keys.CopyPath):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, copyToClipboardCmd(b.filetree.GetSelectedItem().FileName(), false))
+		}
+	case key.Matches(msg, b.keys.CopyContents):
+		if !b.filetree.IsFiltering() && !b.filetree.GetSelectedItem().IsDirectory() {
+			cmds = append(cmds, copyToClipboardCmd(b.filetree.GetSelectedItem().FileName(), true))
+		}
+	case key.Matches(msg, b.keys.Delete):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.deleteSelectedItems())
+		}
+	case key.Matches(msg, b.keys.Select):
+		if !b.filetree.IsFiltering() {
+			b.toggleSelection()
+		}
+	case key.Matches(msg, b.keys.AddBookmark):
+		if !b.filetree.IsFiltering() {
+			b.startInput(bookmarkNameInputMode, "Bookmark name: ", "")
+		}
+	case key.Matches(msg, b.keys.ShowBookmarks):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, listBookmarksCmd())
+		}
+	case key.Matches(msg, b.keys.ShowMounts):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, listMountsCmd())
+		}
+	case key.Matches(msg, b.keys.NewFromTemplate):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, listTemplatesCmd())
+		}
+	case key.Matches(msg, b.keys.FromClipboard):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, readClipboardCmd())
+		}
+	case key.Matches(msg, b.keys.GoToLine):
+		if b.activeBox == 1 {
+			cmds = append(cmds, b.startGoToLine())
+		}
+	case key.Matches(msg, b.keys.NewTab):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.newTab()...)
+		}
+	case key.Matches(msg, b.keys.CloseTab):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.closeTab()...)
+		}
+	case key.Matches(msg, b.keys.NextTab):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.switchTab(b.tabs.active+1))
+		}
+	case key.Matches(msg, b.keys.PreviousTab):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.switchTab(b.tabs.active-1))
+		}
+	case key.Matches(msg, b.keys.Edit):
+		selectedFile := b.filetree.GetSelectedItem()
+		if !b.filetree.IsFiltering() && selectedFile.FileName() != "" && !selectedFile.IsDirectory() {
+			cmds = append(cmds, openEditorCmd(selectedFile.FileName(), b.config.Settings.Editor))
+		}
+	case key.Matches(msg, b.keys.OpenShell):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, openShellCmd(b.currentDirectory(), b.config.Settings.Shell))
+		}
+	case key.Matches(msg, b.keys.CreateFile):
+		if !b.filetree.IsFiltering() {
+			b.startInput(createFileInputMode, "New file: ", "")
+		}
+	case key.Matches(msg, b.keys.CreateDirectory):
+		if !b.filetree.IsFiltering() {
+			b.startInput(createDirectoryInputMode, "New directory: ", "")
+		}
+	case key.Matches(msg, b.keys.Copy):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.markForPaste(false))
+		}
+	case key.Matches(msg, b.keys.Cut):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.markForPaste(true))
+		}
+	case key.Matches(msg, b.keys.Paste):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.paste())
+		}
+	case key.Matches(msg, b.keys.ToggleSizes):
+		if !b.filetree.IsFiltering() {
+			b.toggleExactSizes()
+		}
+	case key.Matches(msg, b.keys.Rename):
+		if !b.filetree.IsFiltering() {
+			b.startRename()
+		}
+	case key.Matches(msg, b.keys.Move):
+		if !b.filetree.IsFiltering() {
+			b.startMove()
+		}
+	case key.Matches(msg, b.keys.SaveAs):
+		if !b.filetree.IsFiltering() {
+			b.startSaveAs()
+		}
+	case key.Matches(msg, b.keys.BulkRename):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.startBulkRename())
+		}
+	case key.Matches(msg, b.keys.Undo):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.undo())
+		}
+	case key.Matches(msg, b.keys.GoToPath):
+		if !b.filetree.IsFiltering() {
+			b.startInput(goToPathInputMode, "Go to: ", "")
+		}
+	case key.Matches(msg, b.keys.GoToStartDir):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.goToStartDir())
+		}
+	case key.Matches(msg, b.keys.Duplicate):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.duplicateSelectedItems())
+		}
+	case key.Matches(msg, b.keys.CycleTheme):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.cycleTheme()...)
+		}
+	case key.Matches(msg, b.keys.ShrinkTree):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.moveDivider(-splitRatioStep)...)
+		}
+	case key.Matches(msg, b.keys.GrowTree):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.moveDivider(splitRatioStep)...)
+		}
+	case key.Matches(msg, b.keys.Find):
+		if !b.filetree.IsFiltering() {
+			cmds = append(cmds, b.openFinder())
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
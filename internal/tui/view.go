@@ -4,9 +4,18 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// statusbarView returns the status bar followed by the status segment.
+func (b Bubble) statusbarView() string {
+	return lipgloss.JoinHorizontal(lipgloss.Top, b.statusbar.View(), b.segment.view)
+}
+
 // View returns a string representation of the UI.
 func (b Bubble) View() string {
-	leftBox := b.filetree.View()
+	filetreeView := b.filetree.View()
+	leftBox := lipgloss.JoinVertical(lipgloss.Left,
+		b.breadcrumbView(lipgloss.Width(filetreeView)),
+		filetreeView,
+	)
 	rightBox := b.help.View()
 
 	switch b.state {
@@ -16,14 +25,41 @@ func (b Bubble) View() string {
 		rightBox = b.code.View()
 	case showImageState:
 		rightBox = b.image.View()
-	case showPdfState:
-		rightBox = b.pdf.View()
-	case showMarkdownState:
-		rightBox = b.markdown.View()
+	case showTextState:
+		rightBox = b.renderer.View()
+	case showPickerState:
+		rightBox = b.picker.View()
+	}
+
+	panes := leftBox
+	if b.showPreview && b.config.Settings.Layout == verticalLayout {
+		panes = lipgloss.JoinVertical(lipgloss.Left, leftBox, rightBox)
+	} else if b.showPreview {
+		panes = lipgloss.JoinHorizontal(lipgloss.Top, leftBox, rightBox)
+	}
+
+	if b.keyHelp.Visible() {
+		panes = b.keyHelp.View()
+	}
+
+	if b.finder.Visible() {
+		panes = b.finder.View()
+	}
+
+	if b.modal.Visible() {
+		panes = b.modal.View()
+	}
+
+	if len(b.tabs.list) > 1 {
+		return lipgloss.JoinVertical(lipgloss.Top,
+			b.tabBarView(),
+			panes,
+			b.statusbarView(),
+		)
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Top,
-		lipgloss.JoinHorizontal(lipgloss.Top, leftBox, rightBox),
-		b.statusbar.View(),
+		panes,
+		b.statusbarView(),
 	)
 }
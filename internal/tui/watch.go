@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/knipferrc/fm/internal/config"
+	"github.com/knipferrc/fm/internal/logger"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long to wait for more events after a change before
+// reacting to it.
+const watchDebounce = 100 * time.Millisecond
+
+// watch is a watcher along with the path it watches.
+type watch struct {
+	watcher    *fsnotify.Watcher
+	path       string
+	failedPath string
+}
+
+type dirChangedMsg struct {
+	dir string
+}
+
+type configChangedMsg struct{}
+
+// waitForDirChangeCmd waits for changes in the watched directory, grouping
+// changes made in quick succession into one message.
+func waitForDirChangeCmd(watcher *fsnotify.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		var dir string
+
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			dir = filepath.Dir(event.Name)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			logger.Error("watching the directory failed", "err", err)
+
+			return dirChangedMsg{}
+		}
+
+		waitForQuiet(watcher)
+
+		return dirChangedMsg{dir: dir}
+	}
+}
+
+// waitForQuiet drops the events of a watcher until none arrived for
+// watchDebounce, or the watcher is closed.
+func waitForQuiet(watcher *fsnotify.Watcher) {
+	timer := time.NewTimer(watchDebounce)
+	defer timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// newConfigWatcher watches the directory of the config file, as editors
+// often save a file by replacing it.
+func newConfigWatcher() (*fsnotify.Watcher, string, error) {
+	path, err := config.FilePath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, "", err
+	}
+
+	return watcher, path, nil
+}
+
+// waitForConfigChangeCmd waits for the config file to change, grouping
+// changes made in quick succession into one message. Changes to other files
+// in its directory are ignored.
+func waitForConfigChangeCmd(watcher *fsnotify.Watcher, path string) tea.Cmd {
+	return func() tea.Msg {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return nil
+				}
+
+				if filepath.Clean(event.Name) == path {
+					waitForQuiet(watcher)
+
+					return configChangedMsg{}
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return nil
+				}
+
+				logger.Error("watching the config file failed", "err", err)
+			}
+		}
+	}
+}
+
+// updateWatcher starts or stops watching the current directory depending on
+// the config, moving the watch along when the current directory changes. A
+// directory which can't be watched isn't tried again until it is left, so the
+// failure is logged once instead of on every update.
+func (b *Bubble) updateWatcher() tea.Cmd {
+	if !b.config.Settings.WatchDirectory {
+		if b.dirWatch.watcher != nil {
+			if err := b.dirWatch.watcher.Close(); err != nil {
+				logger.Error("closing the directory watcher failed", "err", err)
+			}
+
+			b.dirWatch.watcher = nil
+			b.dirWatch.path = ""
+		}
+
+		b.dirWatch.failedPath = ""
+
+		return nil
+	}
+
+	if b.currentDir == b.dirWatch.failedPath {
+		return nil
+	}
+
+	b.dirWatch.failedPath = ""
+
+	var cmd tea.Cmd
+	if b.dirWatch.watcher == nil {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			logger.Error("creating the directory watcher failed", "err", err)
+			b.dirWatch.failedPath = b.currentDir
+
+			return nil
+		}
+
+		b.dirWatch.watcher = watcher
+		cmd = waitForDirChangeCmd(watcher)
+	}
+
+	if b.currentDir == "" || b.currentDir == b.dirWatch.path {
+		return cmd
+	}
+
+	if b.dirWatch.path != "" {
+		if err := b.dirWatch.watcher.Remove(b.dirWatch.path); err != nil {
+			logger.Error("unwatching the directory failed", "dir", b.dirWatch.path, "err", err)
+		}
+	}
+
+	b.dirWatch.path = ""
+	if err := b.dirWatch.watcher.Add(b.currentDir); err != nil {
+		logger.Error("watching the directory failed", "dir", b.currentDir, "err", err)
+		b.dirWatch.failedPath = b.currentDir
+
+		return cmd
+	}
+
+	b.dirWatch.path = b.currentDir
+
+	return cmd
+}
+
+// handleDirChange lists the current directory again after it changed on
+// disk, keeping the cursor on the selected item.
+func (b *Bubble) handleDirChange(msg dirChangedMsg) tea.Cmd {
+	if b.dirWatch.watcher == nil {
+		return nil
+	}
+
+	if msg.dir != "" && msg.dir == b.currentDir {
+		b.pendingSelection = pendingSelection{
+			dir:  b.currentDir,
+			name: filepath.Base(b.filetree.GetSelectedItem().FileName()),
+		}
+
+		return tea.Batch(b.refreshFiletree(), waitForDirChangeCmd(b.dirWatch.watcher))
+	}
+
+	return waitForDirChangeCmd(b.dirWatch.watcher)
+}